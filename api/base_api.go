@@ -41,7 +41,15 @@ func (api *BaseApi) getCurrentCoinbase() common.Address {
 
 func (api *BaseApi) getSignedTx(from common.Address, to *common.Address, txType types.TxType, amount decimal.Decimal,
 	maxFee decimal.Decimal, tips decimal.Decimal, nonce uint32, epoch uint16, payload []byte,
-	key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	key *ecdsa.PrivateKey) (tx *types.Transaction, reserved bool, err error) {
+
+	if epoch == 0 {
+		epoch = api.getAppState().State.Epoch()
+	}
+	if nonce == 0 {
+		nonce = api.txpool.ReserveNonce(from, epoch)
+		reserved = true
+	}
 
 	// if maxFee is not set, we set it as 2x from fee
 	if maxFee == (decimal.Decimal{}) || maxFee == decimal.Zero {
@@ -50,26 +58,49 @@ func (api *BaseApi) getSignedTx(from common.Address, to *common.Address, txType
 		maxFee = blockchain.ConvertToFloat(new(big.Int).Mul(txFee, big.NewInt(2)))
 	}
 
-	tx := blockchain.BuildTx(api.getAppState(), from, to, txType, amount, maxFee, tips, nonce, epoch, payload)
+	tx = blockchain.BuildTx(api.getAppState(), from, to, txType, amount, maxFee, tips, nonce, epoch, payload)
 
-	return api.signTransaction(from, tx, key)
+	signedTx, err := api.signTransaction(from, tx, key)
+	if err != nil {
+		if reserved {
+			api.txpool.ReleaseNonce(from, epoch, nonce)
+		}
+		return nil, false, err
+	}
+	return signedTx, reserved, nil
+}
+
+// completeNonceReservation confirms or releases the nonce reserved for the tx depending on the pool's verdict,
+// explicit nonces are not reserved by getSignedTx and are left as is
+func (api *BaseApi) completeNonceReservation(tx *types.Transaction, reserved bool, err error) {
+	if !reserved {
+		return
+	}
+	sender, _ := types.Sender(tx)
+	if err != nil {
+		api.txpool.ReleaseNonce(sender, tx.Epoch, tx.AccountNonce)
+	} else {
+		api.txpool.ConfirmNonce(sender, tx.Epoch, tx.AccountNonce)
+	}
 }
 
 func (api *BaseApi) sendTx(from common.Address, to *common.Address, txType types.TxType, amount decimal.Decimal,
 	maxFee decimal.Decimal, tips decimal.Decimal, nonce uint32, epoch uint16, payload []byte,
 	key *ecdsa.PrivateKey) (common.Hash, error) {
 
-	signedTx, err := api.getSignedTx(from, to, txType, amount, maxFee, tips, nonce, epoch, payload, key)
+	signedTx, reserved, err := api.getSignedTx(from, to, txType, amount, maxFee, tips, nonce, epoch, payload, key)
 
 	if err != nil {
 		return common.Hash{}, err
 	}
 
-	return api.sendInternalTx(signedTx)
+	return api.sendInternalTx(signedTx, reserved)
 }
 
-func (api *BaseApi) sendInternalTx(tx *types.Transaction) (common.Hash, error) {
-	if err := api.txpool.Add(tx); err != nil {
+func (api *BaseApi) sendInternalTx(tx *types.Transaction, reserved bool) (common.Hash, error) {
+	err := api.txpool.Add(tx)
+	api.completeNonceReservation(tx, reserved, err)
+	if err != nil {
 		return common.Hash{}, err
 	}
 
@@ -10,6 +10,7 @@ import (
 	"github.com/idena-network/idena-go/ipfs"
 	"github.com/idena-network/idena-go/protocol"
 	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	"math/big"
 	"sort"
@@ -76,6 +77,8 @@ type Transaction struct {
 	BlockHash common.Hash     `json:"blockHash"`
 	UsedFee   decimal.Decimal `json:"usedFee"`
 	Timestamp uint64          `json:"timestamp"`
+	// Queued is set for pending txs which cannot be mined until missing nonces are filled or the next epoch begins
+	Queued bool `json:"queued,omitempty"`
 }
 
 type BurntCoins struct {
@@ -169,13 +172,19 @@ type TransactionsArgs struct {
 }
 
 type Transactions struct {
-	Transactions []*Transaction `json:"transactions"`
-	Token        *hexutil.Bytes `json:"token"`
+	Transactions  []*Transaction `json:"transactions"`
+	Token         *hexutil.Bytes `json:"token"`
+	MissingNonces []uint32       `json:"missingNonces,omitempty"`
 }
 
 // sorted by epoch \ nonce desc (the newest transactions are first)
 func (api *BlockchainApi) PendingTransactions(args TransactionsArgs) Transactions {
-	txs := api.pool.GetPendingByAddress(args.Address)
+	senderTxs := api.pool.GetSenderTxs(args.Address)
+	queued := make(map[common.Hash]bool)
+	for _, tx := range senderTxs.Queued {
+		queued[tx.Hash()] = true
+	}
+	txs := append(senderTxs.Executable, senderTxs.Queued...)
 
 	sort.SliceStable(txs, func(i, j int) bool {
 		if txs[i].Epoch > txs[j].Epoch {
@@ -189,12 +198,15 @@ func (api *BlockchainApi) PendingTransactions(args TransactionsArgs) Transaction
 
 	var list []*Transaction
 	for _, item := range txs {
-		list = append(list, convertToTransaction(item, common.Hash{}, nil, 0))
+		tx := convertToTransaction(item, common.Hash{}, nil, 0)
+		tx.Queued = queued[item.Hash()]
+		list = append(list, tx)
 	}
 
 	return Transactions{
-		Transactions: list,
-		Token:        nil,
+		Transactions:  list,
+		Token:         nil,
+		MissingNonces: senderTxs.MissingNonces,
 	}
 }
 
@@ -231,6 +243,53 @@ func (api *BlockchainApi) BurntCoins() []BurntCoins {
 	return res
 }
 
+type AccountDiff struct {
+	Address    common.Address  `json:"address"`
+	OldBalance decimal.Decimal `json:"oldBalance"`
+	NewBalance decimal.Decimal `json:"newBalance"`
+	OldStake   decimal.Decimal `json:"oldStake"`
+	NewStake   decimal.Decimal `json:"newStake"`
+	OldNonce   uint32          `json:"oldNonce"`
+	NewNonce   uint32          `json:"newNonce"`
+	OldState   string          `json:"oldState"`
+	NewState   string          `json:"newState"`
+	OldPenalty decimal.Decimal `json:"oldPenalty"`
+	NewPenalty decimal.Decimal `json:"newPenalty"`
+}
+
+type StateDiff struct {
+	Height   uint64         `json:"height"`
+	Accounts []*AccountDiff `json:"accounts"`
+}
+
+// StateDiff returns accounts and identities changed by the block including rewards and penalties
+func (api *BlockchainApi) StateDiff(height uint64) (*StateDiff, error) {
+	diff := api.bc.GetStateDiff(height)
+	if diff == nil {
+		return nil, errors.Errorf("state diff of block %v is not found", height)
+	}
+	res := &StateDiff{
+		Height:   height,
+		Accounts: []*AccountDiff{},
+	}
+	for _, item := range diff.Accounts {
+		res.Accounts = append(res.Accounts, &AccountDiff{
+			Address:    item.Address,
+			OldBalance: blockchain.ConvertToFloat(item.OldBalance),
+			NewBalance: blockchain.ConvertToFloat(item.NewBalance),
+			OldStake:   blockchain.ConvertToFloat(item.OldStake),
+			NewStake:   blockchain.ConvertToFloat(item.NewStake),
+			OldNonce:   item.OldNonce,
+			NewNonce:   item.NewNonce,
+			OldState:   convertIdentityState(item.OldState),
+			NewState:   convertIdentityState(item.NewState),
+			OldPenalty: blockchain.ConvertToFloat(item.OldPenalty),
+			NewPenalty: blockchain.ConvertToFloat(item.NewPenalty),
+		})
+	}
+	return res, nil
+}
+
 func convertToTransaction(tx *types.Transaction, blockHash common.Hash, feePerByte *big.Int, timestamp uint64) *Transaction {
 	sender, _ := types.Sender(tx)
 	return &Transaction{
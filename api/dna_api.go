@@ -1,6 +1,8 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
 	"fmt"
@@ -9,29 +11,38 @@ import (
 	"github.com/idena-network/idena-go/blockchain/attachments"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
 	"github.com/idena-network/idena-go/common/hexutil"
 	"github.com/idena-network/idena-go/core/ceremony"
+	"github.com/idena-network/idena-go/core/invites"
 	"github.com/idena-network/idena-go/core/profile"
 	"github.com/idena-network/idena-go/core/state"
 	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/events"
+	"github.com/idena-network/idena-go/log"
 	"github.com/idena-network/idena-go/rlp"
+	"github.com/idena-network/idena-go/rpc"
 	"github.com/ipfs/go-cid"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
+	"sort"
 	"time"
 )
 
 type DnaApi struct {
-	bc             *blockchain.Blockchain
-	baseApi        *BaseApi
-	ceremony       *ceremony.ValidationCeremony
-	appVersion     string
-	profileManager *profile.Manager
+	bc              *blockchain.Blockchain
+	baseApi         *BaseApi
+	ceremony        *ceremony.ValidationCeremony
+	appVersion      string
+	profileManager  *profile.Manager
+	offlineDetector *blockchain.OfflineDetector
+	bus             eventbus.Bus
+	invites         *invites.Manager
 }
 
 func NewDnaApi(baseApi *BaseApi, bc *blockchain.Blockchain, ceremony *ceremony.ValidationCeremony, appVersion string,
-	profileManager *profile.Manager) *DnaApi {
-	return &DnaApi{bc, baseApi, ceremony, appVersion, profileManager}
+	profileManager *profile.Manager, offlineDetector *blockchain.OfflineDetector, bus eventbus.Bus, invites *invites.Manager) *DnaApi {
+	return &DnaApi{bc, baseApi, ceremony, appVersion, profileManager, offlineDetector, bus, invites}
 }
 
 type State struct {
@@ -109,6 +120,10 @@ func (api *DnaApi) SendInvite(args SendInviteArgs) (Invite, error) {
 		return Invite{}, err
 	}
 
+	if err := api.invites.Add(receiver, hash, blockchain.ConvertToInt(args.Amount), key); err != nil {
+		log.Warn("Cannot save invite", "receiver", receiver.Hex(), "err", err)
+	}
+
 	var stringKey string
 	if key != nil {
 		stringKey = hex.EncodeToString(crypto.FromECDSA(key))
@@ -183,6 +198,57 @@ func (api *DnaApi) SendTransaction(args SendTxArgs) (common.Hash, error) {
 	return api.baseApi.sendTx(args.From, args.To, args.Type, args.Amount, args.MaxFee, decimal.Zero, args.Nonce, args.Epoch, payload, nil)
 }
 
+type SendTxResult struct {
+	Hash  common.Hash `json:"hash"`
+	Error string      `json:"error,omitempty"`
+}
+
+// SendTransactions signs and submits txs in the given order, txs without explicit nonce get consecutive nonces
+// of their senders. A rejected tx makes the following txs of the same sender be skipped.
+func (api *DnaApi) SendTransactions(args []SendTxArgs) []SendTxResult {
+	epoch := api.baseApi.getAppState().State.Epoch()
+
+	counts := make(map[common.Address]int)
+	for _, item := range args {
+		if item.Nonce == 0 {
+			counts[item.From]++
+		}
+	}
+	nextNonces := make(map[common.Address]uint32)
+	for from, count := range counts {
+		nextNonces[from] = api.baseApi.txpool.ReserveNonces(from, epoch, count)
+	}
+
+	failed := make(map[common.Address]bool)
+	results := make([]SendTxResult, 0, len(args))
+	for _, item := range args {
+		nonce, txEpoch := item.Nonce, item.Epoch
+		if nonce == 0 {
+			nonce, txEpoch = nextNonces[item.From], epoch
+			nextNonces[item.From]++
+			if failed[item.From] {
+				api.baseApi.txpool.ReleaseNonce(item.From, txEpoch, nonce)
+				results = append(results, SendTxResult{Error: "previous tx of the sender is rejected"})
+				continue
+			}
+		}
+		var payload []byte
+		if item.Payload != nil {
+			payload = *item.Payload
+		}
+		hash, err := api.baseApi.sendTx(item.From, item.To, item.Type, item.Amount, item.MaxFee, decimal.Zero, nonce, txEpoch, payload, nil)
+		if err != nil {
+			if item.Nonce == 0 {
+				failed[item.From] = true
+			}
+			results = append(results, SendTxResult{Error: err.Error()})
+			continue
+		}
+		results = append(results, SendTxResult{Hash: hash})
+	}
+	return results
+}
+
 type FlipWords struct {
 	Words [2]uint32 `json:"words"`
 	Used  bool      `json:"used"`
@@ -254,33 +320,7 @@ func (api *DnaApi) Identity(address *common.Address) Identity {
 }
 
 func convertIdentity(currentEpoch uint16, address common.Address, data state.Identity, flipKeyWordPairs []int) Identity {
-	var s string
-	switch data.State {
-	case state.Invite:
-		s = "Invite"
-		break
-	case state.Candidate:
-		s = "Candidate"
-		break
-	case state.Newbie:
-		s = "Newbie"
-		break
-	case state.Verified:
-		s = "Verified"
-		break
-	case state.Suspended:
-		s = "Suspended"
-		break
-	case state.Zombie:
-		s = "Zombie"
-		break
-	case state.Killed:
-		s = "Killed"
-		break
-	default:
-		s = "Undefined"
-		break
-	}
+	s := convertIdentityState(data.State)
 
 	var profileHash string
 	if len(data.ProfileHash) > 0 {
@@ -500,3 +540,329 @@ func (api *DnaApi) Profile(address *common.Address) (ProfileResponse, error) {
 		Info:     info,
 	}, nil
 }
+
+type Activity struct {
+	Address  common.Address `json:"address"`
+	LastSeen *time.Time     `json:"lastSeen"`
+	Online   bool           `json:"online"`
+}
+
+func (api *DnaApi) Activity(address *common.Address) Activity {
+	if address == nil {
+		coinbase := api.GetCoinbaseAddr()
+		address = &coinbase
+	}
+	res := Activity{
+		Address: *address,
+		Online:  api.baseApi.getAppState().ValidatorsCache.IsOnlineIdentity(*address),
+	}
+	if lastSeen, ok := api.offlineDetector.GetActivity(*address); ok {
+		res.LastSeen = &lastSeen
+	}
+	return res
+}
+
+type OfflineCandidate struct {
+	Address       common.Address `json:"address"`
+	BlockHash     common.Hash    `json:"blockHash"`
+	Round         uint64         `json:"round"`
+	Votes         int            `json:"votes"`
+	RequiredVotes int            `json:"requiredVotes"`
+	ProposedAt    *time.Time     `json:"proposedAt"`
+}
+
+func (api *DnaApi) OfflineCandidates() []OfflineCandidate {
+	var res []OfflineCandidate
+	for _, candidate := range api.offlineDetector.GetOfflineCandidates() {
+		var proposedAt *time.Time
+		if !candidate.ProposedAt.IsZero() {
+			t := candidate.ProposedAt
+			proposedAt = &t
+		}
+		res = append(res, OfflineCandidate{
+			Address:       candidate.Addr,
+			BlockHash:     candidate.BlockHash,
+			Round:         candidate.Round,
+			Votes:         candidate.Votes,
+			RequiredVotes: candidate.RequiredVotes,
+			ProposedAt:    proposedAt,
+		})
+	}
+	return res
+}
+
+type OfflinePenalty struct {
+	Address   common.Address  `json:"address"`
+	BlockHash common.Hash     `json:"blockHash"`
+	Height    uint64          `json:"height"`
+	Timestamp uint64          `json:"timestamp"`
+	Penalty   decimal.Decimal `json:"penalty"`
+}
+
+func (api *DnaApi) OfflinePenalties(address *common.Address) []OfflinePenalty {
+	var res []OfflinePenalty
+	for _, penalty := range api.bc.ReadOfflinePenalties(address) {
+		res = append(res, OfflinePenalty{
+			Address:   penalty.Address,
+			BlockHash: penalty.BlockHash,
+			Height:    penalty.Height,
+			Timestamp: penalty.Timestamp,
+			Penalty:   blockchain.ConvertToFloat(penalty.Penalty),
+		})
+	}
+	return res
+}
+
+func convertIdentityState(identityState state.IdentityState) string {
+	return state.IdentityStateName(identityState)
+}
+
+type DisqualifiedFlip struct {
+	Cid     string `json:"cid"`
+	Session string `json:"session"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+}
+
+type ValidationResult struct {
+	Address           common.Address     `json:"address"`
+	Epoch             uint16             `json:"epoch"`
+	PrevState         string             `json:"prevState"`
+	NewState          string             `json:"newState"`
+	Reason            string             `json:"reason"`
+	Failed            bool               `json:"failed"`
+	Candidate         bool               `json:"candidate"`
+	Approved          bool               `json:"approved"`
+	Missed            bool               `json:"missed"`
+	ShortPoints       float32            `json:"shortPoints"`
+	ShortFlips        uint32             `json:"shortFlips"`
+	ShortScore        float32            `json:"shortScore"`
+	ShortAnswersError string             `json:"shortAnswersError,omitempty"`
+	NoQualShort       bool               `json:"noQualShort"`
+	LongPoints        float32            `json:"longPoints"`
+	LongFlips         uint32             `json:"longFlips"`
+	LongScore         float32            `json:"longScore"`
+	LongAnswersError  string             `json:"longAnswersError,omitempty"`
+	NoQualLong        bool               `json:"noQualLong"`
+	TotalPoints       float32            `json:"totalPoints"`
+	TotalFlips        uint32             `json:"totalFlips"`
+	TotalScore        float32            `json:"totalScore"`
+	DisqualifiedFlips []DisqualifiedFlip `json:"disqualifiedFlips"`
+}
+
+// ValidationResult explains the identity state determined by the validation ceremony of the epoch,
+// the previous epoch is used by default
+func (api *DnaApi) ValidationResult(address common.Address, epoch *uint16) (*ValidationResult, error) {
+	var e uint16
+	if epoch != nil {
+		e = *epoch
+	} else {
+		currentEpoch := api.baseApi.getAppState().State.Epoch()
+		if currentEpoch == 0 {
+			return nil, errors.New("no validation has been finished yet")
+		}
+		e = currentEpoch - 1
+	}
+	result := api.bc.ReadValidationResult(e, address)
+	if result == nil {
+		return nil, errors.Errorf("validation result of %v for epoch %v is not found", address.Hex(), e)
+	}
+	res := &ValidationResult{
+		Address:           result.Address,
+		Epoch:             result.Epoch,
+		PrevState:         convertIdentityState(state.IdentityState(result.PrevState)),
+		NewState:          convertIdentityState(state.IdentityState(result.NewState)),
+		Reason:            result.Reason,
+		Failed:            result.Failed,
+		Candidate:         result.Candidate,
+		Approved:          result.Approved,
+		Missed:            result.Missed,
+		ShortPoints:       float32(result.ShortPoints) / 2,
+		ShortFlips:        result.ShortFlips,
+		ShortScore:        score(result.ShortPoints, result.ShortFlips),
+		ShortAnswersError: result.ShortAnswersError,
+		NoQualShort:       result.NoQualShort,
+		LongPoints:        float32(result.LongPoints) / 2,
+		LongFlips:         result.LongFlips,
+		LongScore:         score(result.LongPoints, result.LongFlips),
+		LongAnswersError:  result.LongAnswersError,
+		NoQualLong:        result.NoQualLong,
+		TotalPoints:       float32(result.TotalPoints) / 2,
+		TotalFlips:        result.TotalFlips,
+		TotalScore:        score(result.TotalPoints, result.TotalFlips),
+		DisqualifiedFlips: []DisqualifiedFlip{},
+	}
+	for _, flip := range result.DisqualifiedFlips {
+		session := "long"
+		if flip.Short {
+			session = "short"
+		}
+		c, _ := cid.Cast(flip.Cid)
+		res.DisqualifiedFlips = append(res.DisqualifiedFlips, DisqualifiedFlip{
+			Cid:     c.String(),
+			Session: session,
+			Status:  ceremony.FlipStatusName(ceremony.FlipStatus(flip.Status)),
+			Reason:  flip.Reason,
+		})
+	}
+	return res, nil
+}
+
+// score converts doubled points to the score
+func score(doubledPoints uint32, flips uint32) float32 {
+	if flips == 0 {
+		return 0
+	}
+	return float32(doubledPoints) / 2 / float32(flips)
+}
+
+type RewardSplit struct {
+	Balance decimal.Decimal `json:"balance"`
+	Stake   decimal.Decimal `json:"stake"`
+}
+
+type IdentityRewardsForecast struct {
+	Address           common.Address `json:"address"`
+	Validation        RewardSplit    `json:"validation"`
+	Flips             RewardSplit    `json:"flips"`
+	Invitations       RewardSplit    `json:"invitations"`
+	Total             RewardSplit    `json:"total"`
+	StrongFlips       int            `json:"strongFlips"`
+	WeakFlips         int            `json:"weakFlips"`
+	SuccessfulInvites int            `json:"successfulInvites"`
+	BadAuthor         bool           `json:"badAuthor"`
+}
+
+type RewardsForecast struct {
+	Epoch             uint16                    `json:"epoch"`
+	Blocks            uint64                    `json:"blocks"`
+	TotalReward       decimal.Decimal           `json:"totalReward"`
+	ValidationReward  decimal.Decimal           `json:"validationReward"`
+	FlipsReward       decimal.Decimal           `json:"flipsReward"`
+	InvitationsReward decimal.Decimal           `json:"invitationsReward"`
+	FoundationPayouts decimal.Decimal           `json:"foundationPayouts"`
+	ZeroWalletFund    decimal.Decimal           `json:"zeroWalletFund"`
+	Rewards           []IdentityRewardsForecast `json:"rewards"`
+}
+
+// RewardsForecast calculates validation rewards which would be paid if the epoch was finished at the current block,
+// every submitted flip is treated as qualified and every invited candidate as validated
+func (api *DnaApi) RewardsForecast(address *common.Address) (RewardsForecast, error) {
+	forecast, err := api.bc.ForecastRewards()
+	if err != nil {
+		return RewardsForecast{}, err
+	}
+	res := RewardsForecast{
+		Epoch:             forecast.Epoch,
+		Blocks:            forecast.Blocks,
+		TotalReward:       blockchain.ConvertToFloat(forecast.TotalReward),
+		ValidationReward:  blockchain.ConvertToFloat(forecast.ValidationReward),
+		FlipsReward:       blockchain.ConvertToFloat(forecast.FlipsReward),
+		InvitationsReward: blockchain.ConvertToFloat(forecast.InvitationsReward),
+		FoundationPayouts: blockchain.ConvertToFloat(forecast.FoundationPayouts),
+		ZeroWalletFund:    blockchain.ConvertToFloat(forecast.ZeroWalletFund),
+	}
+	convert := func(addr common.Address, rewards *blockchain.IdentityRewardsForecast) IdentityRewardsForecast {
+		item := IdentityRewardsForecast{
+			Address:     addr,
+			Validation:  convertRewardPart(rewards.Validation),
+			Flips:       convertRewardPart(rewards.Flips),
+			Invitations: convertRewardPart(rewards.Invitations),
+		}
+		item.Total = RewardSplit{
+			Balance: item.Validation.Balance.Add(item.Flips.Balance).Add(item.Invitations.Balance),
+			Stake:   item.Validation.Stake.Add(item.Flips.Stake).Add(item.Invitations.Stake),
+		}
+		if author, ok := forecast.Authors.GoodAuthors[addr]; ok {
+			item.StrongFlips = author.StrongFlips
+			item.WeakFlips = author.WeakFlips
+			item.SuccessfulInvites = author.SuccessfulInvites
+		}
+		_, item.BadAuthor = forecast.Authors.BadAuthors[addr]
+		return item
+	}
+	if address != nil {
+		rewards, ok := forecast.Identities[*address]
+		if !ok {
+			rewards = &blockchain.IdentityRewardsForecast{}
+		}
+		res.Rewards = append(res.Rewards, convert(*address, rewards))
+		return res, nil
+	}
+	for addr, rewards := range forecast.Identities {
+		res.Rewards = append(res.Rewards, convert(addr, rewards))
+	}
+	sort.Slice(res.Rewards, func(i, j int) bool {
+		return bytes.Compare(res.Rewards[i].Address[:], res.Rewards[j].Address[:]) < 0
+	})
+	return res, nil
+}
+
+func convertRewardPart(part *blockchain.RewardPart) RewardSplit {
+	if part == nil {
+		return RewardSplit{Balance: decimal.Zero, Stake: decimal.Zero}
+	}
+	return RewardSplit{
+		Balance: blockchain.ConvertToFloat(part.Balance),
+		Stake:   blockchain.ConvertToFloat(part.Stake),
+	}
+}
+
+type CeremonyProgress struct {
+	Epoch  uint16       `json:"epoch"`
+	Stage  string       `json:"stage"`
+	Count  int          `json:"count,omitempty"`
+	TxType string       `json:"txType,omitempty"`
+	TxHash *common.Hash `json:"txHash,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// CeremonyProgress notifies about every validation ceremony milestone of the node, available over websocket only
+func (api *DnaApi) CeremonyProgress(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	progress := make(chan *events.CeremonyProgressEvent, 16)
+	busSub := api.bus.Subscribe(events.CeremonyProgressEventID, func(e eventbus.Event) {
+		select {
+		case progress <- e.(*events.CeremonyProgressEvent):
+		default:
+		}
+	})
+
+	go func() {
+		defer api.bus.Unsubscribe(busSub)
+		for {
+			select {
+			case e := <-progress:
+				notifier.Notify(rpcSub.ID, convertCeremonyProgress(e))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func convertCeremonyProgress(e *events.CeremonyProgressEvent) *CeremonyProgress {
+	res := &CeremonyProgress{
+		Epoch: e.Epoch,
+		Stage: e.Stage,
+		Count: e.Count,
+		Error: e.Error,
+	}
+	if e.TxType > 0 {
+		res.TxType = txTypeMap[e.TxType]
+	}
+	if e.TxHash != (common.Hash{}) {
+		hash := e.TxHash
+		res.TxHash = &hash
+	}
+	return res
+}
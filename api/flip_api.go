@@ -21,11 +21,12 @@ type FlipApi struct {
 	fp        *flip.Flipper
 	ipfsProxy ipfs.Proxy
 	ceremony  *ceremony.ValidationCeremony
+	archiver  *ceremony.Archiver
 }
 
 // NewFlipApi creates a new FlipApi instance
-func NewFlipApi(baseApi *BaseApi, fp *flip.Flipper, ipfsProxy ipfs.Proxy, ceremony *ceremony.ValidationCeremony) *FlipApi {
-	return &FlipApi{baseApi, fp, ipfsProxy, ceremony}
+func NewFlipApi(baseApi *BaseApi, fp *flip.Flipper, ipfsProxy ipfs.Proxy, ceremony *ceremony.ValidationCeremony, archiver *ceremony.Archiver) *FlipApi {
+	return &FlipApi{baseApi, fp, ipfsProxy, ceremony, archiver}
 }
 
 type FlipSubmitResponse struct {
@@ -53,7 +54,7 @@ func (api *FlipApi) Submit(args FlipSubmitArgs) (FlipSubmitResponse, error) {
 
 	addr := api.baseApi.getCurrentCoinbase()
 
-	tx, err := api.baseApi.getSignedTx(addr, nil, types.SubmitFlipTx, decimal.Zero, decimal.Zero, decimal.Zero, 0, 0, attachments.CreateFlipSubmitAttachment(cid.Bytes(), args.PairId), nil)
+	tx, reserved, err := api.baseApi.getSignedTx(addr, nil, types.SubmitFlipTx, decimal.Zero, decimal.Zero, decimal.Zero, 0, 0, attachments.CreateFlipSubmitAttachment(cid.Bytes(), args.PairId), nil)
 
 	if err != nil {
 		return FlipSubmitResponse{}, err
@@ -64,7 +65,9 @@ func (api *FlipApi) Submit(args FlipSubmitArgs) (FlipSubmitResponse, error) {
 		Data: encryptedFlip,
 	}
 
-	if err := api.fp.AddNewFlip(flip, true); err != nil {
+	err = api.fp.AddNewFlip(flip, true)
+	api.baseApi.completeNonceReservation(tx, reserved, err)
+	if err != nil {
 		return FlipSubmitResponse{}, err
 	}
 	log.Info("flip submitted", "hash", tx.Hash(), "nonce", tx.AccountNonce)
@@ -139,8 +142,101 @@ func prepareHashes(flipper *flip.Flipper, flips [][]byte, shortSession bool) ([]
 	return result, nil
 }
 
+type FlipReadiness struct {
+	Hash        string `json:"hash"`
+	Extra       bool   `json:"extra"`
+	Fetched     bool   `json:"fetched"`
+	KeyReceived bool   `json:"keyReceived"`
+	Ready       bool   `json:"ready"`
+	Error       string `json:"error,omitempty"`
+}
+
+type FlipReadinessResponse struct {
+	Ready   bool             `json:"ready"`
+	Percent float64          `json:"percent"`
+	Short   []*FlipReadiness `json:"short"`
+	Long    []*FlipReadiness `json:"long"`
+}
+
+// Readiness reports whether flips assigned to the coinbase are downloaded and can be decrypted
+func (api *FlipApi) Readiness() (*FlipReadinessResponse, error) {
+	period := api.baseApi.getAppState().State.ValidationPeriod()
+
+	if period != state.FlipLotteryPeriod && period != state.ShortSessionPeriod && period != state.LongSessionPeriod {
+		return nil, errors.New("this method is available during FlipLottery, ShortSession and LongSession periods")
+	}
+
+	if !api.isCeremonyCandidate() {
+		return nil, errors.New("coinbase address is not a ceremony candidate")
+	}
+
+	res := &FlipReadinessResponse{
+		Short: api.prepareReadiness(api.ceremony.GetShortFlipsToSolve(), true),
+		Long:  api.prepareReadiness(api.ceremony.GetLongFlipsToSolve(), false),
+	}
+
+	// short and long sessions may share flips, so every flip is counted once
+	readiness := make(map[string]bool)
+	for _, list := range [][]*FlipReadiness{res.Short, res.Long} {
+		for _, item := range list {
+			readiness[item.Hash] = item.Ready
+		}
+	}
+	ready := 0
+	for _, isReady := range readiness {
+		if isReady {
+			ready++
+		}
+	}
+	if len(readiness) > 0 {
+		res.Percent = float64(ready) * 100 / float64(len(readiness))
+	}
+	res.Ready = len(readiness) > 0 && ready == len(readiness)
+	return res, nil
+}
+
+func (api *FlipApi) prepareReadiness(flips [][]byte, shortSession bool) []*FlipReadiness {
+	result := make([]*FlipReadiness, 0, len(flips))
+	for i, v := range flips {
+		cid, _ := cid.Parse(v)
+		item := &FlipReadiness{
+			Hash:  cid.String(),
+			Extra: shortSession && i >= int(common.ShortSessionFlipsCount()),
+		}
+		author, ok := api.ceremony.GetFlipAuthor(v)
+		if !ok {
+			item.Error = "flip author not found"
+			result = append(result, item)
+			continue
+		}
+		readiness := api.fp.GetFlipReadiness(v, author)
+		item.Fetched = readiness.Fetched
+		item.KeyReceived = readiness.KeyReceived
+		item.Ready = readiness.Ready
+		item.Error = readiness.Error
+		result = append(result, item)
+	}
+	return result
+}
+
 type FlipResponse struct {
 	Hex hexutil.Bytes `json:"hex"`
+	// Images and Orders are the decoded flip, they are empty if the flip has unknown format
+	Images []hexutil.Bytes `json:"images,omitempty"`
+	Orders [][]uint        `json:"orders,omitempty"`
+}
+
+func convertFlip(data []byte) FlipResponse {
+	res := FlipResponse{
+		Hex: hexutil.Bytes(data),
+	}
+	if content, err := flip.DecodeFlip(data); err == nil {
+		for _, img := range content.Images {
+			res.Images = append(res.Images, img)
+		}
+		res.Orders = content.Orders
+	}
+	return res
 }
 
 func (api *FlipApi) Get(hash string) (FlipResponse, error) {
@@ -156,9 +252,7 @@ func (api *FlipApi) Get(hash string) (FlipResponse, error) {
 		return FlipResponse{}, err
 	}
 
-	return FlipResponse{
-		Hex: hexutil.Bytes(data),
-	}, nil
+	return convertFlip(data), nil
 }
 
 type FlipAnswer struct {
@@ -267,3 +361,75 @@ func prepareAnswers(answers []FlipAnswer, flips [][]byte) *types.Answers {
 
 	return result
 }
+
+// ArchivedEpochs returns epochs with archived own flips and answers
+func (api *FlipApi) ArchivedEpochs() ([]uint16, error) {
+	return api.archiver.Epochs()
+}
+
+// Archive returns own flips, answers, assigned flips and their qualification of the archived epoch
+func (api *FlipApi) Archive(epoch uint16) (*ceremony.EpochArchive, error) {
+	return api.archiver.Read(epoch)
+}
+
+// ArchivedFlip returns the decrypted own flip of the archived epoch
+func (api *FlipApi) ArchivedFlip(epoch uint16, hash string) (FlipResponse, error) {
+	data, err := api.archiver.ReadFlip(epoch, hash)
+	if err != nil {
+		return FlipResponse{}, err
+	}
+	return convertFlip(data), nil
+}
+
+// LotteryData returns the lottery seed and ceremony candidates with their flips of the current epoch,
+// it allows to recompute the flip distribution independently
+func (api *FlipApi) LotteryData() (*ceremony.LotteryData, error) {
+	return api.ceremony.LotteryData()
+}
+
+type VerifyLotteryArgs struct {
+	Address    *common.Address `json:"address"`
+	ShortFlips []string        `json:"shortFlips"`
+	LongFlips  []string        `json:"longFlips"`
+}
+
+// VerifyLottery recomputes flips to solve of the address and compares them with the given lists,
+// the coinbase and its flips to solve are used by default
+func (api *FlipApi) VerifyLottery(args VerifyLotteryArgs) (*ceremony.LotteryVerification, error) {
+	data, err := api.ceremony.LotteryData()
+	if err != nil {
+		return nil, err
+	}
+	address := api.baseApi.getCurrentCoinbase()
+	if args.Address != nil {
+		address = *args.Address
+	}
+	var shortFlips, longFlips [][]byte
+	if args.ShortFlips != nil {
+		if shortFlips, err = decodeCids(args.ShortFlips); err != nil {
+			return nil, err
+		}
+	} else if address == api.baseApi.getCurrentCoinbase() {
+		shortFlips = api.ceremony.GetShortFlipsToSolve()
+	}
+	if args.LongFlips != nil {
+		if longFlips, err = decodeCids(args.LongFlips); err != nil {
+			return nil, err
+		}
+	} else if address == api.baseApi.getCurrentCoinbase() {
+		longFlips = api.ceremony.GetLongFlipsToSolve()
+	}
+	return data.Verify(address, shortFlips, longFlips)
+}
+
+func decodeCids(hashes []string) ([][]byte, error) {
+	result := make([][]byte, 0, len(hashes))
+	for _, hash := range hashes {
+		c, err := cid.Decode(hash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid flip hash %v", hash)
+		}
+		result = append(result, c.Bytes())
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/hex"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/invites"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// InvitesApi provides invites issued by the node with the current status of invitees
+type InvitesApi struct {
+	baseApi *BaseApi
+	invites *invites.Manager
+}
+
+// NewInvitesApi creates a new InvitesApi instance
+func NewInvitesApi(baseApi *BaseApi, invites *invites.Manager) *InvitesApi {
+	return &InvitesApi{baseApi, invites}
+}
+
+type SavedInvite struct {
+	Receiver         common.Address  `json:"receiver"`
+	Address          common.Address  `json:"address"`
+	Hash             common.Hash     `json:"hash"`
+	Epoch            uint16          `json:"epoch"`
+	Amount           decimal.Decimal `json:"amount"`
+	Timestamp        uint64          `json:"timestamp"`
+	HasKey           bool            `json:"hasKey"`
+	MinedHeight      uint64          `json:"minedHeight"`
+	ActivationTxHash *common.Hash    `json:"activationTxHash"`
+	KillTxHash       *common.Hash    `json:"killTxHash"`
+	State            string          `json:"state"`
+	Status           string          `json:"status"`
+	ExpiresAt        *time.Time      `json:"expiresAt"`
+	ExpiringSoon     bool            `json:"expiringSoon"`
+}
+
+type InviteKey struct {
+	Receiver common.Address `json:"receiver"`
+	Key      string         `json:"key"`
+}
+
+type RevokeInviteArgs struct {
+	Receiver common.Address `json:"receiver"`
+	BaseTxArgs
+}
+
+// List returns invites of the coinbase, the newest are first
+func (api *InvitesApi) List() []SavedInvite {
+	res := []SavedInvite{}
+	for _, invite := range api.invites.Invites() {
+		res = append(res, convertSavedInvite(invite))
+	}
+	return res
+}
+
+func (api *InvitesApi) Get(receiver common.Address) (SavedInvite, error) {
+	invite, err := api.invites.Invite(receiver)
+	if err != nil {
+		return SavedInvite{}, err
+	}
+	return convertSavedInvite(invite), nil
+}
+
+// Key returns the decrypted key of the invite generated by the node
+func (api *InvitesApi) Key(receiver common.Address) (InviteKey, error) {
+	key, err := api.invites.Key(receiver)
+	if err != nil {
+		return InviteKey{}, err
+	}
+	return InviteKey{
+		Receiver: receiver,
+		Key:      hex.EncodeToString(crypto.FromECDSA(key)),
+	}, nil
+}
+
+// Revoke kills the invitee by KillInviteeTx, the invite stake is returned to the inviter if the invitee is not validated yet
+func (api *InvitesApi) Revoke(args RevokeInviteArgs) (common.Hash, error) {
+	address, err := api.invites.RevocableAddress(args.Receiver)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return api.baseApi.sendTx(api.baseApi.getCurrentCoinbase(), &address, types.KillInviteeTx, decimal.Zero, decimal.Zero, decimal.Zero, args.Nonce, args.Epoch, nil, nil)
+}
+
+func convertSavedInvite(invite *invites.Invite) SavedInvite {
+	res := SavedInvite{
+		Receiver:     invite.Receiver,
+		Address:      invite.Address,
+		Hash:         invite.TxHash,
+		Epoch:        invite.Epoch,
+		Amount:       blockchain.ConvertToFloat(invite.Amount),
+		Timestamp:    invite.Timestamp,
+		HasKey:       len(invite.EncryptedKey) > 0,
+		MinedHeight:  invite.MinedHeight,
+		State:        convertIdentityState(invite.State),
+		Status:       invite.Status,
+		ExpiringSoon: invite.ExpiringSoon,
+	}
+	if invite.ActivationTxHash != (common.Hash{}) {
+		hash := invite.ActivationTxHash
+		res.ActivationTxHash = &hash
+	}
+	if invite.KillTxHash != (common.Hash{}) {
+		hash := invite.KillTxHash
+		res.KillTxHash = &hash
+	}
+	if !invite.ExpiresAt.IsZero() {
+		expiresAt := invite.ExpiresAt
+		res.ExpiresAt = &expiresAt
+	}
+	return res
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/common"
+	statsTypes "github.com/idena-network/idena-go/stats/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"sort"
+)
+
+// StatsApi provides rewards and validation stats of finished epochs, the node should be started with stats collecting
+type StatsApi struct {
+	bc *blockchain.Blockchain
+}
+
+// NewStatsApi creates a new StatsApi instance
+func NewStatsApi(bc *blockchain.Blockchain) *StatsApi {
+	return &StatsApi{bc}
+}
+
+type EpochStats struct {
+	Epoch                uint16          `json:"epoch"`
+	Height               uint64          `json:"height"`
+	ValidationFailed     bool            `json:"validationFailed"`
+	Candidates           uint32          `json:"candidates"`
+	Flips                uint32          `json:"flips"`
+	QualifiedFlips       uint32          `json:"qualifiedFlips"`
+	WeaklyQualifiedFlips uint32          `json:"weaklyQualifiedFlips"`
+	GoodAuthors          uint32          `json:"goodAuthors"`
+	BadAuthors           uint32          `json:"badAuthors"`
+	TotalReward          decimal.Decimal `json:"totalReward"`
+	ValidationReward     decimal.Decimal `json:"validationReward"`
+	FlipsReward          decimal.Decimal `json:"flipsReward"`
+	InvitationsReward    decimal.Decimal `json:"invitationsReward"`
+	FoundationPayouts    decimal.Decimal `json:"foundationPayouts"`
+	ZeroWalletFund       decimal.Decimal `json:"zeroWalletFund"`
+}
+
+type AddressStats struct {
+	Address           common.Address  `json:"address"`
+	Epoch             uint16          `json:"epoch"`
+	Height            uint64          `json:"height"`
+	Validation        RewardSplit     `json:"validation"`
+	Flips             RewardSplit     `json:"flips"`
+	Invitations       RewardSplit     `json:"invitations"`
+	Total             RewardSplit     `json:"total"`
+	FoundationPayout  decimal.Decimal `json:"foundationPayout"`
+	ZeroWalletFund    decimal.Decimal `json:"zeroWalletFund"`
+	Candidate         bool            `json:"candidate"`
+	Approved          bool            `json:"approved"`
+	Missed            bool            `json:"missed"`
+	ShortPoints       float32         `json:"shortPoints"`
+	ShortFlips        uint32          `json:"shortFlips"`
+	LongPoints        float32         `json:"longPoints"`
+	LongFlips         uint32          `json:"longFlips"`
+	StrongFlips       uint32          `json:"strongFlips"`
+	WeakFlips         uint32          `json:"weakFlips"`
+	SuccessfulInvites uint32          `json:"successfulInvites"`
+	BadAuthor         bool            `json:"badAuthor"`
+}
+
+type EpochRewards struct {
+	EpochStats
+	Addresses []*AddressStats `json:"addresses"`
+}
+
+// Epochs returns totals of epochs having stats, the latest epoch is first
+func (api *StatsApi) Epochs() []*EpochStats {
+	stats := api.bc.ReadStats()
+	res := make([]*EpochStats, 0, len(stats))
+	for i := len(stats) - 1; i >= 0; i-- {
+		res = append(res, convertEpochStats(stats[i]))
+	}
+	return res
+}
+
+// Epoch returns totals and rewards per address of the epoch, the latest epoch having stats is used by default
+func (api *StatsApi) Epoch(epoch *uint16) (*EpochRewards, error) {
+	stats := api.bc.ReadStats()
+	if len(stats) == 0 {
+		return nil, errors.New("no stats have been collected")
+	}
+	blockStats := stats[len(stats)-1]
+	if epoch != nil {
+		blockStats = findEpochStats(stats, *epoch)
+		if blockStats == nil {
+			return nil, errors.Errorf("stats of epoch %v are not found", *epoch)
+		}
+	}
+	res := &EpochRewards{
+		EpochStats: *convertEpochStats(blockStats),
+		Addresses:  []*AddressStats{},
+	}
+	for _, item := range api.bc.ReadAddressStats(blockStats.Height, nil) {
+		res.Addresses = append(res.Addresses, convertAddressStats(item))
+	}
+	sort.SliceStable(res.Addresses, func(i, j int) bool {
+		return res.Addresses[i].Total.Balance.Add(res.Addresses[i].Total.Stake).
+			GreaterThan(res.Addresses[j].Total.Balance.Add(res.Addresses[j].Total.Stake))
+	})
+	return res, nil
+}
+
+// Address returns rewards and validation results of the address for every epoch having stats, the latest epoch is first
+func (api *StatsApi) Address(address common.Address) []*AddressStats {
+	stats := api.bc.ReadStats()
+	res := make([]*AddressStats, 0)
+	for i := len(stats) - 1; i >= 0; i-- {
+		for _, item := range api.bc.ReadAddressStats(stats[i].Height, &address) {
+			res = append(res, convertAddressStats(item))
+		}
+	}
+	return res
+}
+
+func findEpochStats(stats []*statsTypes.BlockStats, epoch uint16) *statsTypes.BlockStats {
+	for _, item := range stats {
+		if item.Epoch == epoch {
+			return item
+		}
+	}
+	return nil
+}
+
+func convertEpochStats(stats *statsTypes.BlockStats) *EpochStats {
+	return &EpochStats{
+		Epoch:                stats.Epoch,
+		Height:               stats.Height,
+		ValidationFailed:     stats.ValidationFailed,
+		Candidates:           stats.Candidates,
+		Flips:                stats.Flips,
+		QualifiedFlips:       stats.QualifiedFlips,
+		WeaklyQualifiedFlips: stats.WeaklyQualified,
+		GoodAuthors:          stats.GoodAuthors,
+		BadAuthors:           stats.BadAuthors,
+		TotalReward:          blockchain.ConvertToFloat(stats.TotalReward),
+		ValidationReward:     blockchain.ConvertToFloat(stats.ValidationReward),
+		FlipsReward:          blockchain.ConvertToFloat(stats.FlipsReward),
+		InvitationsReward:    blockchain.ConvertToFloat(stats.InvitationsReward),
+		FoundationPayouts:    blockchain.ConvertToFloat(stats.FoundationPayouts),
+		ZeroWalletFund:       blockchain.ConvertToFloat(stats.ZeroWalletFund),
+	}
+}
+
+func convertAddressStats(stats *statsTypes.AddressStats) *AddressStats {
+	res := &AddressStats{
+		Address:           stats.Address,
+		Epoch:             stats.Epoch,
+		Height:            stats.Height,
+		Validation:        convertRewardPart(&blockchain.RewardPart{Balance: stats.ValidationReward, Stake: stats.ValidationStake}),
+		Flips:             convertRewardPart(&blockchain.RewardPart{Balance: stats.FlipsReward, Stake: stats.FlipsStake}),
+		Invitations:       convertRewardPart(&blockchain.RewardPart{Balance: stats.InvitationsReward, Stake: stats.InvitationsStake}),
+		FoundationPayout:  blockchain.ConvertToFloat(stats.FoundationPayout),
+		ZeroWalletFund:    blockchain.ConvertToFloat(stats.ZeroWalletFund),
+		Candidate:         stats.Candidate,
+		Approved:          stats.Approved,
+		Missed:            stats.Missed,
+		ShortPoints:       float32(stats.ShortPoints) / 2,
+		ShortFlips:        stats.ShortFlips,
+		LongPoints:        float32(stats.LongPoints) / 2,
+		LongFlips:         stats.LongFlips,
+		StrongFlips:       stats.StrongFlips,
+		WeakFlips:         stats.WeakFlips,
+		SuccessfulInvites: stats.SuccessfulInvites,
+		BadAuthor:         stats.BadAuthor,
+	}
+	balance := new(big.Int).Add(stats.ValidationReward, stats.FlipsReward)
+	balance.Add(balance, stats.InvitationsReward)
+	stake := new(big.Int).Add(stats.ValidationStake, stats.FlipsStake)
+	stake.Add(stake, stats.InvitationsStake)
+	res.Total = RewardSplit{
+		Balance: blockchain.ConvertToFloat(balance),
+		Stake:   blockchain.ConvertToFloat(stake),
+	}
+	return res
+}
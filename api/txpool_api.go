@@ -0,0 +1,170 @@
+package api
+
+import (
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/mempool"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// TxPoolApi allows to inspect the content of the transaction pool
+type TxPoolApi struct {
+	pool         *mempool.TxPool
+	ownTxWatcher *mempool.OwnTxWatcher
+}
+
+// NewTxPoolApi creates a new TxPoolApi instance
+func NewTxPoolApi(pool *mempool.TxPool, ownTxWatcher *mempool.OwnTxWatcher) *TxPoolApi {
+	return &TxPoolApi{pool, ownTxWatcher}
+}
+
+type TxPoolStatus struct {
+	Executable int                    `json:"executable"`
+	Queued     int                    `json:"queued"`
+	Deferred   int                    `json:"deferred"`
+	Types      map[string]int         `json:"types"`
+	Senders    map[common.Address]int `json:"senders"`
+}
+
+type TxPoolTransaction struct {
+	Hash       common.Hash     `json:"hash"`
+	Type       string          `json:"type"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"`
+	Amount     decimal.Decimal `json:"amount"`
+	MaxFee     decimal.Decimal `json:"maxFee"`
+	Tips       decimal.Decimal `json:"tips"`
+	FeePerByte decimal.Decimal `json:"feePerByte"`
+	Nonce      uint32          `json:"nonce"`
+	Epoch      uint16          `json:"epoch"`
+	Arrival    int64           `json:"arrival"`
+	Age        int64           `json:"age"`
+	Status     string          `json:"status"`
+	SkipReason string          `json:"skipReason,omitempty"`
+}
+
+func (api *TxPoolApi) Status() TxPoolStatus {
+	status := TxPoolStatus{
+		Types:   make(map[string]int),
+		Senders: make(map[common.Address]int),
+	}
+	for _, info := range api.pool.Inspect(nil) {
+		switch {
+		case info.Deferred:
+			status.Deferred++
+		case info.Executable:
+			status.Executable++
+		default:
+			status.Queued++
+		}
+		sender, _ := types.Sender(info.Tx)
+		status.Types[txTypeMap[info.Tx.Type]]++
+		status.Senders[sender]++
+	}
+	return status
+}
+
+// Inspect returns txs of the address sorted by epoch \ nonce
+func (api *TxPoolApi) Inspect(address common.Address) []*TxPoolTransaction {
+	infos := api.pool.Inspect(&address)
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Tx.Epoch != infos[j].Tx.Epoch {
+			return infos[i].Tx.Epoch < infos[j].Tx.Epoch
+		}
+		return infos[i].Tx.AccountNonce < infos[j].Tx.AccountNonce
+	})
+	now := time.Now()
+	list := make([]*TxPoolTransaction, 0, len(infos))
+	for _, info := range infos {
+		list = append(list, convertToTxPoolTransaction(info, now))
+	}
+	return list
+}
+
+func convertToTxPoolTransaction(info *mempool.TxInfo, now time.Time) *TxPoolTransaction {
+	tx := info.Tx
+	sender, _ := types.Sender(tx)
+	status := "queued"
+	if info.Deferred {
+		status = "deferred"
+	} else if info.Executable {
+		status = "executable"
+	}
+	feePerByte := new(big.Int).Div(tx.MaxFeeOrZero(), big.NewInt(int64(tx.Size())))
+	return &TxPoolTransaction{
+		Hash:       tx.Hash(),
+		Type:       txTypeMap[tx.Type],
+		From:       sender,
+		To:         tx.To,
+		Amount:     blockchain.ConvertToFloat(tx.Amount),
+		MaxFee:     blockchain.ConvertToFloat(tx.MaxFee),
+		Tips:       blockchain.ConvertToFloat(tx.Tips),
+		FeePerByte: blockchain.ConvertToFloat(feePerByte),
+		Nonce:      tx.AccountNonce,
+		Epoch:      tx.Epoch,
+		Arrival:    info.Arrival.Unix(),
+		Age:        int64(now.Sub(info.Arrival).Seconds()),
+		Status:     status,
+		SkipReason: info.SkipReason,
+	}
+}
+
+type DroppedTransaction struct {
+	Transaction *Transaction `json:"transaction"`
+	Reason      string       `json:"reason"`
+	Timestamp   int64        `json:"timestamp"`
+}
+
+// Dropped returns recently dropped own txs which should be rebuilt and sent again
+func (api *TxPoolApi) Dropped() []*DroppedTransaction {
+	dropped := api.pool.GetDroppedOwnTxs()
+	list := make([]*DroppedTransaction, 0, len(dropped))
+	for i := len(dropped) - 1; i >= 0; i-- {
+		list = append(list, &DroppedTransaction{
+			Transaction: convertToTransaction(dropped[i].Tx, common.Hash{}, nil, 0),
+			Reason:      dropped[i].Reason,
+			Timestamp:   dropped[i].Time.Unix(),
+		})
+	}
+	return list
+}
+
+type OwnTransaction struct {
+	Transaction   *Transaction `json:"transaction"`
+	State         string       `json:"state"`
+	Submitted     int64        `json:"submitted"`
+	Broadcasts    int          `json:"broadcasts"`
+	LastBroadcast int64        `json:"lastBroadcast"`
+	BlockHash     *common.Hash `json:"blockHash,omitempty"`
+	BlockHeight   uint64       `json:"blockHeight,omitempty"`
+	ReplacedBy    *common.Hash `json:"replacedBy,omitempty"`
+	DropReason    string       `json:"dropReason,omitempty"`
+}
+
+// OwnTransactions returns the lifecycle of txs sent by the node, the newest are first
+func (api *TxPoolApi) OwnTransactions() []*OwnTransaction {
+	ownTxs := api.ownTxWatcher.GetOwnTxs()
+	list := make([]*OwnTransaction, 0, len(ownTxs))
+	for _, ownTx := range ownTxs {
+		item := &OwnTransaction{
+			Transaction:   convertToTransaction(ownTx.Tx, ownTx.BlockHash, nil, 0),
+			State:         ownTx.State,
+			Submitted:     ownTx.Submitted.Unix(),
+			Broadcasts:    ownTx.Broadcasts,
+			LastBroadcast: ownTx.LastBroadcast.Unix(),
+			BlockHeight:   ownTx.BlockHeight,
+			ReplacedBy:    ownTx.ReplacedBy,
+			DropReason:    ownTx.DropReason,
+		}
+		if ownTx.State == mempool.OwnTxMined {
+			blockHash := ownTx.BlockHash
+			item.BlockHash = &blockHash
+		}
+		list = append(list, item)
+	}
+	return list
+}
@@ -27,10 +27,12 @@ import (
 	"github.com/idena-network/idena-go/rlp"
 	"github.com/idena-network/idena-go/secstore"
 	"github.com/idena-network/idena-go/stats/collector"
+	statsTypes "github.com/idena-network/idena-go/stats/types"
 	cid2 "github.com/ipfs/go-cid"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	dbm "github.com/tendermint/tm-db"
+	"io/ioutil"
 	math2 "math"
 	"math/big"
 	"time"
@@ -124,13 +126,16 @@ func (chain *Blockchain) InitializeChain() error {
 
 	chain.coinBaseAddress = chain.secStore.GetAddress()
 	chain.pubKey = chain.secStore.GetPubKey()
+	chain.appState.State.SetTrackChanges(chain.config.StateDiff.Enabled)
 	head := chain.GetHead()
 	if head != nil {
 		chain.setCurrentHead(head)
 		genesisHeight := uint64(1)
 
-		if chain.config.Network == Testnet {
-			predefinedState, err := readPredefinedState()
+		if height, _, ok := chain.repo.ReadGenesis(); ok {
+			genesisHeight = height
+		} else if chain.config.Network == Testnet {
+			predefinedState, err := readPredefinedState("")
 			if err != nil {
 				return err
 			}
@@ -176,14 +181,18 @@ func (chain *Blockchain) GenerateGenesis(network types.Network) (*types.Block, e
 		}
 	}
 
-	chain.appState.State.SetGodAddress(chain.config.GenesisConf.GodAddress)
+	godAddress := chain.genesisGodAddress()
+	if chain.config.GenesisConf.GodBalance != nil {
+		chain.appState.State.AddBalance(godAddress, chain.config.GenesisConf.GodBalance)
+	}
+	chain.appState.State.SetGodAddress(godAddress)
 
-	seed := types.Seed(crypto.Keccak256Hash(append([]byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6}, common.ToBytes(network)...)))
+	seed := genesisSeed(network)
 	blockNumber := uint64(1)
 	var feePerByte *big.Int
 
-	if network == Testnet {
-		predefinedState, err := readPredefinedState()
+	if network == Testnet || chain.config.GenesisConf.PredefinedState != "" {
+		predefinedState, err := readPredefinedState(chain.config.GenesisConf.PredefinedState)
 		if err != nil {
 			return nil, err
 		}
@@ -205,6 +214,10 @@ func (chain *Blockchain) GenerateGenesis(network types.Network) (*types.Block, e
 		}
 		chain.appState.State.SetNextValidationTime(time.Unix(nextValidationTimestamp, 0))
 		chain.appState.State.SetFlipWordsSeed(seed)
+		if chain.config.GenesisConf.FeePerByte != nil {
+			feePerByte = chain.config.GenesisConf.FeePerByte
+			chain.appState.State.SetFeePerByte(feePerByte)
+		}
 
 		log.Info("Next validation time", "time", chain.appState.State.NextValidationTime().String(), "unix", nextValidationTimestamp)
 	}
@@ -228,13 +241,24 @@ func (chain *Blockchain) GenerateGenesis(network types.Network) (*types.Block, e
 		},
 	}, Body: &types.Body{}}
 
-	if err := chain.insertBlock(block, new(state.IdentityStateDiff)); err != nil {
+	if err := chain.insertBlock(block, new(state.IdentityStateDiff), nil, nil); err != nil {
 		return nil, err
 	}
+	chain.repo.WriteGenesis(blockNumber, network)
 	chain.genesis = block.Header
 	return block, nil
 }
 
+// genesisGodAddress returns the configured god address, devnet uses the address of the node which creates the genesis
+// if it is not configured since the devnet has no predefined god
+func (chain *Blockchain) genesisGodAddress() common.Address {
+	godAddress := chain.config.GenesisConf.GodAddress
+	if godAddress == (common.Address{}) && chain.config.NetworkProfile == config.DevnetProfile {
+		return chain.coinBaseAddress
+	}
+	return godAddress
+}
+
 func (chain *Blockchain) generateEmptyBlock(checkState *appstate.AppState, prevBlock *types.Header) *types.Block {
 	prevTimestamp := time.Unix(prevBlock.Time().Int64(), 0)
 
@@ -273,12 +297,12 @@ func (chain *Blockchain) AddBlock(block *types.Block, checkState *appstate.AppSt
 	}
 	chain.blockStatsCollector.EnableCollecting()
 	defer chain.blockStatsCollector.CompleteCollecting()
-	diff, err := chain.processBlock(block)
+	diff, stateDiff, offlinePenalty, err := chain.processBlock(block)
 	if err != nil {
 		return err
 	}
 
-	if err := chain.insertBlock(block, diff); err != nil {
+	if err := chain.insertBlock(block, diff, stateDiff, offlinePenalty); err != nil {
 		return err
 	}
 
@@ -293,29 +317,38 @@ func (chain *Blockchain) AddBlock(block *types.Block, checkState *appstate.AppSt
 	return nil
 }
 
-func (chain *Blockchain) processBlock(block *types.Block) (diff *state.IdentityStateDiff, err error) {
+func (chain *Blockchain) processBlock(block *types.Block) (diff *state.IdentityStateDiff, stateDiff *state.StateDiff, offlinePenalty *big.Int, err error) {
 	var root, identityRoot common.Hash
 	if block.IsEmpty() {
 		root, identityRoot, diff = chain.applyEmptyBlockOnState(chain.appState, block)
 	} else {
 		if root, identityRoot, diff, err = chain.applyBlockOnState(chain.appState, block, chain.Head); err != nil {
 			chain.appState.Reset()
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	if root != block.Root() || identityRoot != block.IdentityRoot() {
 		chain.appState.Reset()
-		return nil, errors.Errorf("Invalid block root. Expected=%x, blockroot=%x", root, block.Root())
+		return nil, nil, nil, errors.Errorf("Invalid block root. Expected=%x, blockroot=%x", root, block.Root())
+	}
+
+	if chain.config.StateDiff.Enabled {
+		stateDiff = chain.appState.State.Diff()
+	}
+
+	// validators cache is not updated until the commit, so the penalty is the same as the applied one
+	if block.Header.Flags().HasFlag(types.OfflineCommit) {
+		offlinePenalty = chain.calculateOfflinePenalty(chain.appState)
 	}
 
 	if err := chain.appState.Commit(block); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	chain.log.Trace("Applied block", "root", fmt.Sprintf("0x%x", block.Root()), "height", block.Height())
 
-	return diff, nil
+	return diff, stateDiff, offlinePenalty, nil
 }
 
 func (chain *Blockchain) applyBlockOnState(appState *appstate.AppState, block *types.Block, prevBlock *types.Header) (root common.Hash, identityRoot common.Hash, diff *state.IdentityStateDiff, err error) {
@@ -504,19 +537,26 @@ func (chain *Blockchain) applyGlobalParams(appState *appstate.AppState, block *t
 }
 
 func (chain *Blockchain) applyOfflinePenalty(appState *appstate.AppState, addr common.Address) {
-	networkSize := appState.ValidatorsCache.NetworkSize()
-
-	if networkSize > 0 {
-		totalBlockReward := new(big.Int).Add(chain.config.Consensus.FinalCommitteeReward, chain.config.Consensus.BlockReward)
-		totalPenalty := new(big.Int).Mul(totalBlockReward, big.NewInt(chain.config.Consensus.OfflinePenaltyBlocksCount))
-		coins := decimal.NewFromBigInt(totalPenalty, 0)
-		res := coins.Div(decimal.New(int64(networkSize), 0))
-		appState.State.SetPenalty(addr, math.ToInt(res))
+	if penalty := chain.calculateOfflinePenalty(appState); penalty != nil {
+		appState.State.SetPenalty(addr, penalty)
 	}
 
 	appState.IdentityState.SetOnline(addr, false)
 }
 
+// calculateOfflinePenalty returns the penalty of the identity which goes offline, it is nil if the penalty is not applied
+func (chain *Blockchain) calculateOfflinePenalty(appState *appstate.AppState) *big.Int {
+	networkSize := appState.ValidatorsCache.NetworkSize()
+	if networkSize == 0 {
+		return nil
+	}
+	totalBlockReward := new(big.Int).Add(chain.config.Consensus.FinalCommitteeReward, chain.config.Consensus.BlockReward)
+	totalPenalty := new(big.Int).Mul(totalBlockReward, big.NewInt(chain.config.Consensus.OfflinePenaltyBlocksCount))
+	coins := decimal.NewFromBigInt(totalPenalty, 0)
+	res := coins.Div(decimal.New(int64(networkSize), 0))
+	return math.ToInt(res)
+}
+
 func (chain *Blockchain) rewardFinalCommittee(appState *appstate.AppState, block *types.Block, prevBlock *types.Header) {
 	if block.IsEmpty() {
 		return
@@ -752,16 +792,16 @@ func getSeedData(prevBlock *types.Header) []byte {
 	return result
 }
 
-func (chain *Blockchain) GetProposerSortition() (bool, common.Hash, []byte) {
+func (chain *Blockchain) GetProposerSortition() (bool, common.Hash, []byte, error) {
 
 	if checkIfProposer(chain.coinBaseAddress, chain.appState) {
 		return chain.getSortition(chain.getProposerData(), chain.appState.State.VrfProposerThreshold())
 	}
 
-	return false, common.Hash{}, nil
+	return false, common.Hash{}, nil, nil
 }
 
-func (chain *Blockchain) ProposeBlock() *types.Block {
+func (chain *Blockchain) ProposeBlock() (*types.Block, error) {
 	head := chain.Head
 
 	txs := chain.txpool.BuildBlockTransactions()
@@ -801,7 +841,11 @@ func (chain *Blockchain) ProposeBlock() *types.Block {
 		Body: body,
 	}
 
-	block.Header.ProposedHeader.BlockSeed, block.Header.ProposedHeader.SeedProof = chain.secStore.VrfEvaluate(getSeedData(head))
+	seed, seedProof, err := chain.secStore.VrfEvaluate(getSeedData(head))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot evaluate block seed")
+	}
+	block.Header.ProposedHeader.BlockSeed, block.Header.ProposedHeader.SeedProof = seed, seedProof
 
 	block.Header.ProposedHeader.TxBloom = calculateTxBloom(block)
 
@@ -822,7 +866,7 @@ func (chain *Blockchain) ProposeBlock() *types.Block {
 	block.Header.ProposedHeader.Root = checkState.State.Root()
 	block.Header.ProposedHeader.IdentityRoot = checkState.IdentityState.Root()
 
-	return block
+	return block, nil
 }
 
 func calculateTxBloom(block *types.Block) []byte {
@@ -922,19 +966,31 @@ func (chain *Blockchain) insertHeader(header *types.Header) {
 	chain.repo.WriteCanonicalHash(header.Height(), header.Hash())
 }
 
-func (chain *Blockchain) insertBlock(block *types.Block, diff *state.IdentityStateDiff) error {
+func (chain *Blockchain) insertBlock(block *types.Block, diff *state.IdentityStateDiff, stateDiff *state.StateDiff, offlinePenalty *big.Int) error {
 	_, err := chain.ipfs.Add(block.Body.Bytes())
 	if err != nil {
 		return errors.Wrap(BlockInsertionErr, err.Error())
 	}
 	chain.insertHeader(block.Header)
 	chain.WriteIdentityStateDiff(block.Height(), diff)
+	chain.saveStateDiff(block.Height(), stateDiff)
 	chain.WriteTxIndex(block.Hash(), block.Body.Transactions)
 	chain.SaveTxs(block.Header, block.Body.Transactions)
+	chain.saveOfflinePenalty(block.Header, offlinePenalty)
 	chain.setCurrentHead(block.Header)
 	return nil
 }
 
+func (chain *Blockchain) saveStateDiff(height uint64, diff *state.StateDiff) {
+	if diff == nil {
+		return
+	}
+	chain.repo.WriteStateDiff(height, diff.Bytes())
+	if retention := chain.config.StateDiff.RetentionBlocks; retention > 0 && height > retention {
+		chain.repo.DeleteStateDiff(height - retention)
+	}
+}
+
 func (chain *Blockchain) WriteTxIndex(hash common.Hash, txs types.Transactions) {
 	for i, tx := range txs {
 		idx := &types.TransactionIndex{
@@ -953,17 +1009,20 @@ func (chain *Blockchain) getProposerData() []byte {
 	return result
 }
 
-func (chain *Blockchain) getSortition(data []byte, threshold float64) (bool, common.Hash, []byte) {
-	hash, proof := chain.secStore.VrfEvaluate(data)
+func (chain *Blockchain) getSortition(data []byte, threshold float64) (bool, common.Hash, []byte, error) {
+	hash, proof, err := chain.secStore.VrfEvaluate(data)
+	if err != nil {
+		return false, common.Hash{}, nil, err
+	}
 
 	v := new(big.Float).SetInt(new(big.Int).SetBytes(hash[:]))
 
 	q := new(big.Float).Quo(v, MaxHash).SetPrec(10)
 
 	if f, _ := q.Float64(); f >= threshold {
-		return true, hash, proof
+		return true, hash, proof, nil
 	}
-	return false, common.Hash{}, nil
+	return false, common.Hash{}, nil, nil
 }
 
 func (chain *Blockchain) validateBlock(checkState *appstate.AppState, block *types.Block, prevBlock *types.Header) error {
@@ -1311,6 +1370,9 @@ func (chain *Blockchain) ResetTo(height uint64) error {
 		chain.repo.RemoveHeader(hash)
 		chain.repo.RemoveCanonicalHash(h)
 	}
+	chain.repo.DeleteOfflinePenalties(height + 1)
+	chain.repo.DeleteStateDiffs(height + 1)
+	chain.repo.DeleteBlockStatsFrom(height + 1)
 
 	return nil
 }
@@ -1414,6 +1476,21 @@ func (chain *Blockchain) GetIdentityDiff(height uint64) *state.IdentityStateDiff
 	return diff
 }
 
+// GetStateDiff returns account changes of the block, it is nil if state diffs are disabled, the block has not been processed
+// by the node or its diff is out of the retention range
+func (chain *Blockchain) GetStateDiff(height uint64) *state.StateDiff {
+	data := chain.repo.ReadStateDiff(height)
+	if data == nil {
+		return nil
+	}
+	diff := new(state.StateDiff)
+	if err := rlp.DecodeBytes(data, diff); err != nil {
+		chain.log.Error("invalid state diff RLP", "err", err)
+		return nil
+	}
+	return diff
+}
+
 func (chain *Blockchain) ReadSnapshotManifest() *snapshot.Manifest {
 	cid, root, height, _ := chain.repo.LastSnapshotManifest()
 	if cid == nil {
@@ -1426,6 +1503,10 @@ func (chain *Blockchain) ReadSnapshotManifest() *snapshot.Manifest {
 	}
 }
 
+func (chain *Blockchain) ReadSnapshotManifestV2() *snapshot.ManifestV2 {
+	return chain.repo.LastSnapshotManifestV2()
+}
+
 func (chain *Blockchain) ReadPreliminaryHead() *types.Header {
 	return chain.repo.ReadPreliminaryHead()
 }
@@ -1477,8 +1558,87 @@ func (chain *Blockchain) ReadTotalBurntCoins() []*types.BurntCoins {
 	return chain.repo.GetTotalBurntCoins()
 }
 
-func readPredefinedState() (*state.PredefinedState, error) {
-	data, err := Asset("stategen.out")
+// saveOfflinePenalty records the penalty applied by the block, nothing is recorded if the penalty has been skipped
+func (chain *Blockchain) saveOfflinePenalty(header *types.Header, penalty *big.Int) {
+	if !header.Flags().HasFlag(types.OfflineCommit) || penalty == nil {
+		return
+	}
+	addr := header.OfflineAddr()
+	chain.repo.WriteOfflinePenalty(&types.OfflinePenalty{
+		Address:   *addr,
+		BlockHash: header.Hash(),
+		Height:    header.Height(),
+		Timestamp: header.Time().Uint64(),
+		Penalty:   penalty,
+	})
+}
+
+func (chain *Blockchain) ReadOfflinePenalties(address *common.Address) []*types.OfflinePenalty {
+	return chain.repo.GetOfflinePenalties(address)
+}
+
+func (chain *Blockchain) ReadValidationResult(epoch uint16, address common.Address) *types.IdentityValidationResult {
+	return chain.repo.ReadValidationResult(epoch, address)
+}
+
+// ReadStats returns stats of blocks which have finished epochs ordered by height asc
+func (chain *Blockchain) ReadStats() []*statsTypes.BlockStats {
+	return chain.repo.GetBlockStats()
+}
+
+func (chain *Blockchain) ReadAddressStats(height uint64, address *common.Address) []*statsTypes.AddressStats {
+	if address == nil {
+		return chain.repo.GetAddressStats(height)
+	}
+	if stats := chain.repo.ReadAddressStats(height, *address); stats != nil {
+		return []*statsTypes.AddressStats{stats}
+	}
+	return nil
+}
+
+func genesisSeed(network types.Network) types.Seed {
+	return types.Seed(crypto.Keccak256Hash(append([]byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6}, common.ToBytes(network)...)))
+}
+
+// ReadChainNetwork returns the network of the chain stored in the db, false is returned if the db is empty.
+// Chains created before the genesis record was introduced are recognized among candidates by the genesis block seed.
+func ReadChainNetwork(db dbm.DB, candidates []types.Network) (types.Network, bool, error) {
+	repo := database.NewRepo(db)
+	if _, network, ok := repo.ReadGenesis(); ok {
+		return network, true, nil
+	}
+	if repo.ReadHead() == nil {
+		return 0, false, nil
+	}
+	readHeader := func(height uint64) *types.Header {
+		return repo.ReadBlockHeader(repo.ReadCanonicalHash(height))
+	}
+	predefinedState, err := readPredefinedState("")
+	if err != nil {
+		return 0, false, err
+	}
+	if genesis := readHeader(predefinedState.Block); genesis != nil && genesis.Seed() == predefinedState.Seed {
+		return Testnet, true, nil
+	}
+	if genesis := readHeader(1); genesis != nil {
+		for _, network := range candidates {
+			if genesis.Seed() == genesisSeed(network) {
+				return network, true, nil
+			}
+		}
+	}
+	return 0, false, errors.New("genesis block does not match any known network")
+}
+
+// readPredefinedState reads the state from the file, the bundled testnet state is used if the path is empty
+func readPredefinedState(path string) (*state.PredefinedState, error) {
+	var data []byte
+	var err error
+	if path != "" {
+		data, err = ioutil.ReadFile(path)
+	} else {
+		data, err = Asset("stategen.out")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +61,8 @@ func NewTestBlockchainWithConfig(withIdentity bool, conf *config.ConsensusConf,
 		},
 		Validation: valConf,
 		Blockchain: &config.BlockchainConfig{},
+		StateDiff:  config.GetDefaultStateDiffConfig(),
+		Mempool:    config.GetDefaultMempoolConfig(),
 	}
 
 	db := db.NewMemDB()
@@ -77,14 +79,16 @@ func NewTestBlockchainWithConfig(withIdentity bool, conf *config.ConsensusConf,
 		}
 	}
 
-	txPool := mempool.NewTxPool(appState, bus, totalTxLimit, addrTxLimit, cfg.Consensus.MinFeePerByte)
+	cfg.Mempool.TotalTxLimit = totalTxLimit
+	cfg.Mempool.AddrTxLimit = addrTxLimit
+	txPool := mempool.NewTxPool(appState, bus, cfg.Mempool, cfg.Consensus.MinFeePerByte)
 	offline := NewOfflineDetector(config.GetDefaultOfflineDetectionConfig(), db, appState, secStore, bus)
 
 	chain := NewBlockchain(cfg, db, txPool, appState, ipfs.NewMemoryIpfsProxy(), secStore, bus, offline, collector.NewBlockStatsCollector())
 
 	chain.InitializeChain()
 	appState.Initialize(chain.Head.Height())
-	txPool.Initialize(chain.Head, secStore.GetAddress())
+	txPool.Initialize(chain.Head, secStore.GetAddress(), "")
 
 	return chain, appState, txPool, key
 }
@@ -115,8 +119,10 @@ func NewCustomTestBlockchain(blocksCount int, emptyBlocksCount int, key *ecdsa.P
 		},
 		Validation: &config.ValidationConfig{},
 		Blockchain: &config.BlockchainConfig{},
+		StateDiff:  config.GetDefaultStateDiffConfig(),
+		Mempool:    config.GetDefaultMempoolConfig(),
 	}
-	txPool := mempool.NewTxPool(appState, bus, -1, -1, cfg.Consensus.MinFeePerByte)
+	txPool := mempool.NewTxPool(appState, bus, cfg.Mempool, cfg.Consensus.MinFeePerByte)
 	offline := NewOfflineDetector(config.GetDefaultOfflineDetectionConfig(), db, appState, secStore, bus)
 
 	chain := NewBlockchain(cfg, db, txPool, appState, ipfs.NewMemoryIpfsProxy(), secStore, bus, offline, collector.NewBlockStatsCollector())
@@ -125,7 +131,7 @@ func NewCustomTestBlockchain(blocksCount int, emptyBlocksCount int, key *ecdsa.P
 
 	result := &TestBlockchain{db, chain}
 	result.GenerateBlocks(blocksCount).GenerateEmptyBlocks(emptyBlocksCount)
-	txPool.Initialize(chain.Head, secStore.GetAddress())
+	txPool.Initialize(chain.Head, secStore.GetAddress(), "")
 	return result, appState
 }
 
@@ -155,8 +161,10 @@ func (chain *TestBlockchain) Copy() (*TestBlockchain, *appstate.AppState) {
 		},
 		Validation: &config.ValidationConfig{},
 		Blockchain: &config.BlockchainConfig{},
+		StateDiff:  config.GetDefaultStateDiffConfig(),
+		Mempool:    config.GetDefaultMempoolConfig(),
 	}
-	txPool := mempool.NewTxPool(appState, bus, -1, -1, cfg.Consensus.MinFeePerByte)
+	txPool := mempool.NewTxPool(appState, bus, cfg.Mempool, cfg.Consensus.MinFeePerByte)
 	offline := NewOfflineDetector(config.GetDefaultOfflineDetectionConfig(), db, appState, chain.secStore, bus)
 
 	copy := NewBlockchain(cfg, db, txPool, appState, ipfs.NewMemoryIpfsProxy(), chain.secStore, bus, offline, collector.NewBlockStatsCollector())
@@ -175,15 +183,22 @@ func (chain *TestBlockchain) addCert(block *types.Block) {
 			TurnOffline: false,
 		},
 	}
-	vote.Signature = chain.secStore.Sign(vote.Header.SignatureHash().Bytes())
+	signature, err := chain.secStore.SignVote(vote.Header)
+	if err != nil {
+		panic(err)
+	}
+	vote.Signature = signature
 	chain.WriteCertificate(block.Hash(), &types.BlockCert{Votes: []*types.Vote{vote}}, true)
 }
 
 func (chain *TestBlockchain) GenerateBlocks(count int) *TestBlockchain {
 	for i := 0; i < count; i++ {
-		block := chain.ProposeBlock()
+		block, err := chain.ProposeBlock()
+		if err != nil {
+			panic(err)
+		}
 		block.Header.ProposedHeader.Time = big.NewInt(0).Add(chain.Head.Time(), big.NewInt(20))
-		err := chain.AddBlock(block, nil)
+		err = chain.AddBlock(block, nil)
 		if err != nil {
 			panic(err)
 		}
@@ -9,9 +9,11 @@ import (
 	"github.com/idena-network/idena-go/config"
 	"github.com/idena-network/idena-go/core/state"
 	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/database"
 	"github.com/idena-network/idena-go/tests"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tm-db"
 	"math/big"
 	"testing"
 	"time"
@@ -427,6 +429,67 @@ func TestBlockchain_ReadBlockForForkedPeer(t *testing.T) {
 	require.Len(t, bundles, 49)
 }
 
+func TestBlockchain_genesisGodAddress(t *testing.T) {
+	chain, _, _, key := NewTestBlockchain(false, nil)
+	require.Equal(t, common.Address{}, chain.genesisGodAddress())
+
+	chain.config.NetworkProfile = config.DevnetProfile
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), chain.genesisGodAddress())
+
+	god := common.Address{0x1}
+	chain.config.GenesisConf.GodAddress = god
+	require.Equal(t, god, chain.genesisGodAddress())
+}
+
+func TestBlockchain_StateDiff(t *testing.T) {
+	require := require.New(t)
+
+	chain, _ := NewTestBlockchainWithBlocks(2, 0)
+	require.Nil(chain.GetStateDiff(chain.Head.Height()))
+
+	chain.config.StateDiff = &config.StateDiffConfig{Enabled: true, RetentionBlocks: 3}
+	chain.appState.State.SetTrackChanges(true)
+	chain.GenerateBlocks(5)
+	require.Equal(uint64(8), chain.Head.Height())
+
+	require.Nil(chain.GetStateDiff(5))
+	for h := uint64(6); h <= 8; h++ {
+		require.False(chain.GetStateDiff(h).Empty())
+	}
+
+	require.NoError(chain.ResetTo(6))
+	require.NotNil(chain.GetStateDiff(6))
+	require.Nil(chain.GetStateDiff(7))
+	require.Nil(chain.GetStateDiff(8))
+}
+
+func TestBlockchain_saveOfflinePenalty(t *testing.T) {
+	require := require.New(t)
+	chain, _, _, _ := NewTestBlockchain(false, nil)
+
+	addr := common.Address{0x1}
+	header := &types.Header{
+		ProposedHeader: &types.ProposedHeader{
+			Height:      2,
+			Time:        big.NewInt(10),
+			Flags:       types.OfflineCommit,
+			OfflineAddr: &addr,
+		},
+	}
+
+	// the penalty is skipped in the network without validators
+	require.Nil(chain.calculateOfflinePenalty(chain.appState))
+	chain.saveOfflinePenalty(header, nil)
+	require.Empty(chain.ReadOfflinePenalties(&addr))
+
+	chain.saveOfflinePenalty(header, big.NewInt(5))
+	penalties := chain.ReadOfflinePenalties(&addr)
+	require.Len(penalties, 1)
+	require.Equal(big.NewInt(5), penalties[0].Penalty)
+	require.Equal(uint64(2), penalties[0].Height)
+	require.Equal(header.Hash(), penalties[0].BlockHash)
+}
+
 func Test_ApplyBurnTx(t *testing.T) {
 	senderKey, _ := crypto.GenerateKey()
 	balance := new(big.Int).Mul(common.DnaBase, big.NewInt(100))
@@ -550,3 +613,44 @@ func Test_Blockchain_SaveBurntCoins(t *testing.T) {
 	require.Equal(addr, burntCoins[0].Address)
 	require.Equal(big.NewInt(1), burntCoins[0].Amount)
 }
+
+func TestReadChainNetwork(t *testing.T) {
+	require := require.New(t)
+
+	chain, _, _, _ := NewTestBlockchain(false, nil)
+	height, network, ok := chain.repo.ReadGenesis()
+	require.True(ok)
+	require.Equal(uint64(1), height)
+	require.Equal(types.Network(0x99), network)
+
+	memDb := db.NewMemDB()
+	network, stored, err := ReadChainNetwork(memDb, []types.Network{0x99})
+	require.NoError(err)
+	require.False(stored)
+
+	// the chain is created before the genesis record was introduced
+	repo := database.NewRepo(memDb)
+	genesis := &types.Header{
+		ProposedHeader: &types.ProposedHeader{
+			Height:    1,
+			BlockSeed: genesisSeed(0x99),
+		},
+	}
+	repo.WriteBlockHeader(genesis)
+	repo.WriteCanonicalHash(1, genesis.Hash())
+	repo.WriteHead(genesis)
+
+	network, stored, err = ReadChainNetwork(memDb, []types.Network{0x2, 0x99})
+	require.NoError(err)
+	require.True(stored)
+	require.Equal(types.Network(0x99), network)
+
+	_, _, err = ReadChainNetwork(memDb, []types.Network{0x2})
+	require.Error(err)
+
+	repo.WriteGenesis(1, 0x2)
+	network, stored, err = ReadChainNetwork(memDb, nil)
+	require.NoError(err)
+	require.True(stored)
+	require.Equal(types.Network(0x2), network)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/idena-network/idena-go/events"
 	"github.com/idena-network/idena-go/secstore"
 	dbm "github.com/tendermint/tm-db"
+	"sort"
 	"sync"
 	"time"
 )
@@ -44,6 +45,16 @@ type voteList struct {
 	voters mapset.Set
 }
 
+type OfflineCandidate struct {
+	Addr          common.Address
+	BlockHash     common.Hash
+	Round         uint64
+	Votes         int
+	RequiredVotes int
+	// time when this node proposed the address to become offline, zero if the proposal was made by another node
+	ProposedAt time.Time
+}
+
 func NewOfflineDetector(config *config.OfflineDetectionConfig, db dbm.DB, appState *appstate.AppState, secStore *secstore.SecStore, bus eventbus.Bus) *OfflineDetector {
 	return &OfflineDetector{
 		config:           config,
@@ -215,6 +226,44 @@ func (dt *OfflineDetector) GetActivityMap() map[common.Address]time.Time {
 	return res
 }
 
+func (dt *OfflineDetector) GetActivity(addr common.Address) (time.Time, bool) {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+	activityTime, ok := dt.activityMap[addr]
+	return activityTime, ok
+}
+
+// GetOfflineCandidates returns pending offline proposals with collected votes ordered by round desc
+func (dt *OfflineDetector) GetOfflineCandidates() []*OfflineCandidate {
+	dt.mutex.Lock()
+	defer dt.mutex.Unlock()
+
+	requiredVotes := dt.appState.ValidatorsCache.OnlineSize()/2 + 1
+	res := make([]*OfflineCandidate, 0, len(dt.offlineVoting))
+	for hash, votes := range dt.offlineVoting {
+		header := dt.repo.ReadBlockHeader(hash)
+		if header == nil || !header.Flags().HasFlag(types.OfflinePropose) {
+			continue
+		}
+		addr := header.OfflineAddr()
+		if addr == nil {
+			continue
+		}
+		res = append(res, &OfflineCandidate{
+			Addr:          *addr,
+			BlockHash:     hash,
+			Round:         votes.round,
+			Votes:         votes.voters.Cardinality(),
+			RequiredVotes: requiredVotes,
+			ProposedAt:    dt.offlineProposals[*addr],
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Round > res[j].Round
+	})
+	return res
+}
+
 func (dt *OfflineDetector) startListening() {
 	for {
 		select {
@@ -0,0 +1,163 @@
+package blockchain
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/state"
+	statsTypes "github.com/idena-network/idena-go/stats/types"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+type RewardPart struct {
+	Balance *big.Int
+	Stake   *big.Int
+}
+
+type IdentityRewardsForecast struct {
+	Validation  *RewardPart
+	Flips       *RewardPart
+	Invitations *RewardPart
+}
+
+type RewardsForecast struct {
+	Epoch             uint16
+	Blocks            uint64
+	Authors           *types.ValidationAuthors
+	TotalReward       *big.Int
+	ValidationReward  *big.Int
+	FlipsReward       *big.Int
+	InvitationsReward *big.Int
+	FoundationPayouts *big.Int
+	ZeroWalletFund    *big.Int
+	Identities        map[common.Address]*IdentityRewardsForecast
+}
+
+func (f *RewardsForecast) identity(addr common.Address) *IdentityRewardsForecast {
+	res, ok := f.Identities[addr]
+	if !ok {
+		res = &IdentityRewardsForecast{}
+		f.Identities[addr] = res
+	}
+	return res
+}
+
+// ForecastRewards runs the epoch reward distribution against the given state which is expected to be a disposable copy
+func ForecastRewards(appState *appstate.AppState, config *config.ConsensusConf, authors *types.ValidationAuthors, blocks uint64) *RewardsForecast {
+	forecast := &RewardsForecast{
+		Epoch:      appState.State.Epoch(),
+		Blocks:     blocks,
+		Authors:    authors,
+		Identities: make(map[common.Address]*IdentityRewardsForecast),
+	}
+	rewardValidIdentities(appState, config, authors, blocks, &forecastCollector{forecast})
+	return forecast
+}
+
+// EstimateValidationAuthors assumes that every submitted flip will be qualified as strong
+// and every invited candidate will pass the validation
+func EstimateValidationAuthors(appState *appstate.AppState) *types.ValidationAuthors {
+	authors := &types.ValidationAuthors{
+		BadAuthors:  make(map[common.Address]struct{}),
+		GoodAuthors: make(map[common.Address]*types.ValidationResult),
+	}
+	god := appState.State.GodAddress()
+	getResult := func(addr common.Address) *types.ValidationResult {
+		vr, ok := authors.GoodAuthors[addr]
+		if !ok {
+			vr = &types.ValidationResult{}
+			authors.GoodAuthors[addr] = vr
+		}
+		return vr
+	}
+	appState.State.IterateOverIdentities(func(addr common.Address, identity state.Identity) {
+		if len(identity.Flips) > 0 {
+			getResult(addr).StrongFlips += len(identity.Flips)
+		}
+	})
+	appState.State.IterateOverIdentities(func(addr common.Address, identity state.Identity) {
+		if identity.State == state.Candidate && identity.Inviter != nil {
+			inviter := identity.Inviter.Address
+			if _, ok := authors.GoodAuthors[inviter]; ok || inviter == god {
+				getResult(inviter).SuccessfulInvites++
+			}
+		}
+	})
+	return authors
+}
+
+func (chain *Blockchain) ForecastRewards() (*RewardsForecast, error) {
+	head := chain.Head
+	appState := chain.appState.Readonly(head.Height())
+	if appState == nil {
+		return nil, errors.Errorf("state is not found at height %v", head.Height())
+	}
+	blocks := head.Height() - appState.State.EpochBlock()
+	return ForecastRewards(appState, chain.config.Consensus, EstimateValidationAuthors(appState), blocks), nil
+}
+
+type forecastCollector struct {
+	forecast *RewardsForecast
+}
+
+func (c *forecastCollector) EnableCollecting() {
+	// do nothing
+}
+
+func (c *forecastCollector) CompleteCollecting() {
+	// do nothing
+}
+
+func (c *forecastCollector) SetValidation(validation *statsTypes.ValidationStats) {
+	// do nothing
+}
+
+func (c *forecastCollector) SetAuthors(authors *types.ValidationAuthors) {
+	// do nothing
+}
+
+func (c *forecastCollector) SetTotalReward(amount *big.Int) {
+	c.forecast.TotalReward = amount
+}
+
+func (c *forecastCollector) SetTotalValidationReward(amount *big.Int) {
+	c.forecast.ValidationReward = amount
+}
+
+func (c *forecastCollector) SetTotalFlipsReward(amount *big.Int) {
+	c.forecast.FlipsReward = amount
+}
+
+func (c *forecastCollector) SetTotalInvitationsReward(amount *big.Int) {
+	c.forecast.InvitationsReward = amount
+}
+
+func (c *forecastCollector) SetTotalFoundationPayouts(amount *big.Int) {
+	c.forecast.FoundationPayouts = amount
+}
+
+func (c *forecastCollector) SetTotalZeroWalletFund(amount *big.Int) {
+	c.forecast.ZeroWalletFund = amount
+}
+
+func (c *forecastCollector) AddValidationReward(addr common.Address, balance *big.Int, stake *big.Int) {
+	c.forecast.identity(addr).Validation = &RewardPart{balance, stake}
+}
+
+func (c *forecastCollector) AddFlipsReward(addr common.Address, balance *big.Int, stake *big.Int) {
+	c.forecast.identity(addr).Flips = &RewardPart{balance, stake}
+}
+
+func (c *forecastCollector) AddInvitationsReward(addr common.Address, balance *big.Int, stake *big.Int) {
+	c.forecast.identity(addr).Invitations = &RewardPart{balance, stake}
+}
+
+func (c *forecastCollector) AddFoundationPayout(addr common.Address, balance *big.Int) {
+	// do nothing
+}
+
+func (c *forecastCollector) AddZeroWalletFund(addr common.Address, balance *big.Int) {
+	// do nothing
+}
@@ -125,3 +125,51 @@ func Test_splitReward(t *testing.T) {
 	require.True(t, big.NewInt(80).Cmp(reward) == 0)
 	require.True(t, big.NewInt(20).Cmp(stake) == 0)
 }
+
+func Test_ForecastRewards(t *testing.T) {
+	god := common.Address{0x1}
+	auth1 := common.Address{0x2}
+	auth2 := common.Address{0x3}
+	candidate := common.Address{0x4}
+
+	conf := GetDefaultConsensusConfig(false)
+	conf.BlockReward = big.NewInt(5)
+	conf.FinalCommitteeReward = big.NewInt(5)
+
+	appState := appstate.NewAppState(db.NewMemDB(), eventbus.New())
+	appState.Initialize(0)
+
+	appState.State.SetGlobalEpoch(5)
+	appState.State.SetGodAddress(god)
+
+	appState.State.SetState(auth1, state.Verified)
+	appState.State.SetBirthday(auth1, 2)
+	appState.State.AddFlip(auth1, []byte{0x1}, 0)
+	appState.State.AddFlip(auth1, []byte{0x2}, 1)
+
+	appState.State.SetState(auth2, state.Newbie)
+	appState.State.SetBirthday(auth2, 5)
+
+	appState.State.SetState(candidate, state.Candidate)
+	appState.State.SetInviter(candidate, auth1, common.Hash{})
+	appState.Commit(nil)
+
+	authors := EstimateValidationAuthors(appState)
+	require.Equal(t, 2, authors.GoodAuthors[auth1].StrongFlips)
+	require.Equal(t, 1, authors.GoodAuthors[auth1].SuccessfulInvites)
+	require.NotContains(t, authors.GoodAuthors, auth2)
+
+	forecast := ForecastRewards(appState, conf, authors, 100)
+
+	require.True(t, big.NewInt(1000).Cmp(forecast.TotalReward) == 0)
+	require.True(t, big.NewInt(320).Cmp(forecast.FlipsReward) == 0)
+	require.True(t, big.NewInt(320).Cmp(forecast.InvitationsReward) == 0)
+
+	reward, stake := splitAndSum(conf, float32(320))
+	require.True(t, reward.Cmp(forecast.Identities[auth1].Flips.Balance) == 0)
+	require.True(t, stake.Cmp(forecast.Identities[auth1].Flips.Stake) == 0)
+	require.True(t, reward.Cmp(forecast.Identities[auth1].Invitations.Balance) == 0)
+	require.Nil(t, forecast.Identities[auth2].Flips)
+	require.NotNil(t, forecast.Identities[auth2].Validation)
+	require.NotContains(t, forecast.Identities, candidate)
+}
@@ -180,6 +180,65 @@ type BurntCoins struct {
 	Amount  *big.Int
 }
 
+type OfflinePenalty struct {
+	Address   common.Address
+	BlockHash common.Hash
+	Height    uint64
+	Timestamp uint64
+	Penalty   *big.Int
+}
+
+// IdentityValidationResult explains the identity state determined by the validation ceremony
+type IdentityValidationResult struct {
+	Address     common.Address
+	Epoch       uint16
+	PrevState   uint8
+	NewState    uint8
+	Candidate   bool
+	Approved    bool
+	Missed      bool
+	NoQualShort bool
+	NoQualLong  bool
+	// points are doubled to store halves as integers
+	ShortPoints       uint32
+	ShortFlips        uint32
+	LongPoints        uint32
+	LongFlips         uint32
+	TotalPoints       uint32
+	TotalFlips        uint32
+	ShortAnswersError string
+	LongAnswersError  string
+	DisqualifiedFlips []*DisqualifiedFlip
+	// Reason is the rule which has produced the new state
+	Reason string
+	// Failed is true if nobody has been validated, identity states are kept unchanged in this case
+	Failed bool
+}
+
+type DisqualifiedFlip struct {
+	Cid    []byte
+	Short  bool
+	Status byte
+	Reason string
+}
+
+// SavedInvite is the invite issued by the node, the activation and revocation are tracked by the blocks
+type SavedInvite struct {
+	Receiver  common.Address
+	Inviter   common.Address
+	TxHash    common.Hash
+	Epoch     uint16
+	Amount    *big.Int
+	Timestamp uint64
+	// EncryptedKey is the generated receiver key encrypted by the node key, it is empty if the receiver is given
+	EncryptedKey []byte
+	MinedHeight  uint64
+	// ActivatedAddress is the invitee address which differs from the receiver if the invite is activated by another key
+	ActivatedAddress common.Address
+	ActivationTxHash common.Hash
+	KillTxHash       common.Hash
+}
+
 func (b *Block) Hash() common.Hash {
 	if hash := b.hash.Load(); hash != nil {
 		return hash.(common.Hash)
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/idena-network/idena-go/cmd/utils"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/ceremony"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/log"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+var (
+	HeightFlag = cli.Uint64Flag{
+		Name:  "height",
+		Usage: "Use the state of the given block of the current epoch, the head is used by default. Applied epochs can't be simulated since their data is cleared",
+	}
+	AddressFlag = cli.StringFlag{
+		Name:  "address",
+		Usage: "Show the result of the given address only",
+	}
+	JsonFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result as json",
+	}
+)
+
+type identityResult struct {
+	Address     common.Address `json:"address"`
+	Candidate   bool           `json:"candidate"`
+	Approved    bool           `json:"approved"`
+	Missed      bool           `json:"missed"`
+	ShortPoint  float32        `json:"shortPoint"`
+	ShortFlips  uint32         `json:"shortFlips"`
+	ShortScore  float32        `json:"shortScore"`
+	LongPoint   float32        `json:"longPoint"`
+	LongFlips   uint32         `json:"longFlips"`
+	LongScore   float32        `json:"longScore"`
+	TotalScore  float32        `json:"totalScore"`
+	TotalFlips  uint32         `json:"totalFlips"`
+	NoQualShort bool           `json:"noQualShort"`
+	NoQualLong  bool           `json:"noQualLong"`
+	PrevState   string         `json:"prevState"`
+	NewState    string         `json:"newState"`
+	Birthday    uint16         `json:"birthday"`
+}
+
+type simulationResult struct {
+	Epoch      uint16            `json:"epoch"`
+	Height     uint64            `json:"height"`
+	Candidates int               `json:"candidates"`
+	Flips      int               `json:"flips"`
+	Failed     bool              `json:"failed"`
+	Identities []*identityResult `json:"identities"`
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Usage = "Re-run validation ceremony qualification offline over the recorded epoch data. " +
+		"The datadir should be taken after the long session and before the validation has been finished, " +
+		"because the node clears the epoch data when the new epoch begins"
+
+	app.Flags = []cli.Flag{
+		config.DataDirFlag,
+		config.VerbosityFlag,
+		HeightFlag,
+		AddressFlag,
+		JsonFlag,
+	}
+
+	app.Action = func(context *cli.Context) error {
+		utils.SetupLogger(context.Int("verbosity"))
+
+		if !context.IsSet(config.DataDirFlag.Name) {
+			return errors.New("datadir option is required")
+		}
+
+		db, err := utils.OpenDatabase(context.String(config.DataDirFlag.Name), "idenachain", 16, 16)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		repo := database.NewRepo(db)
+
+		head := repo.ReadHead()
+		if head == nil {
+			return errors.New("head is not found")
+		}
+		height := head.Height()
+		if context.IsSet(HeightFlag.Name) {
+			height = context.Uint64(HeightFlag.Name)
+		}
+		if height > head.Height() {
+			return errors.Errorf("height %v is greater than the head height %v", height, head.Height())
+		}
+
+		appState := appstate.NewAppState(db, eventbus.New())
+		if err := appState.Initialize(height); err != nil {
+			return errors.Wrapf(err, "cannot load state at height %v", height)
+		}
+		if height < head.Height() {
+			headState := appstate.NewAppState(db, eventbus.New())
+			if err := headState.Initialize(head.Height()); err != nil {
+				return errors.Wrapf(err, "cannot load state at height %v", head.Height())
+			}
+			if epoch := appState.State.Epoch(); epoch < headState.State.Epoch() {
+				return errors.Errorf("epoch %v of height %v has already been applied and its data is cleared, "+
+					"use a datadir taken before the validation has been finished", epoch, height)
+			}
+		}
+
+		simulation, err := ceremony.SimulateEpoch(appState, db)
+		if err != nil {
+			return err
+		}
+
+		result := &simulationResult{
+			Epoch:      simulation.Epoch,
+			Height:     height,
+			Candidates: simulation.Candidates,
+			Flips:      simulation.Flips,
+			Failed:     simulation.Failed,
+		}
+		var address *common.Address
+		if context.IsSet(AddressFlag.Name) {
+			addr := common.HexToAddress(context.String(AddressFlag.Name))
+			address = &addr
+		}
+		for _, identity := range simulation.Identities {
+			if address != nil && identity.Address != *address {
+				continue
+			}
+			result.Identities = append(result.Identities, convertIdentity(identity))
+		}
+		sort.Slice(result.Identities, func(i, j int) bool {
+			return result.Identities[i].Address.Hex() < result.Identities[j].Address.Hex()
+		})
+
+		if context.Bool(JsonFlag.Name) {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		}
+
+		fmt.Printf("Epoch: %v, height: %v\n", result.Epoch, result.Height)
+		fmt.Printf("Candidates: %v, flips: %v\n", result.Candidates, result.Flips)
+		if result.Failed {
+			fmt.Println("Validation failed: nobody is validated, identity states remain the same")
+		}
+		fmt.Println()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ADDRESS\tAPPROVED\tSHORT\tLONG\tTOTAL\tPREV STATE\tNEW STATE")
+		for _, identity := range result.Identities {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", identity.Address.Hex(), identity.Approved,
+				formatScore(identity.ShortPoint, identity.ShortFlips, identity.ShortScore),
+				formatScore(identity.LongPoint, identity.LongFlips, identity.LongScore),
+				fmt.Sprintf("%.3f (%v flips)", identity.TotalScore, identity.TotalFlips),
+				identity.PrevState, identity.NewState)
+		}
+		return w.Flush()
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+	}
+}
+
+func convertIdentity(identity *ceremony.SimulatedIdentity) *identityResult {
+	return &identityResult{
+		Address:     identity.Address,
+		Candidate:   identity.Candidate,
+		Approved:    identity.Approved,
+		Missed:      identity.Missed,
+		ShortPoint:  identity.ShortPoint,
+		ShortFlips:  identity.ShortFlips,
+		ShortScore:  identity.ShortScore,
+		LongPoint:   identity.LongPoint,
+		LongFlips:   identity.LongFlips,
+		LongScore:   identity.LongScore,
+		TotalScore:  identity.TotalScore,
+		TotalFlips:  identity.TotalFlips,
+		NoQualShort: identity.NoQualShort,
+		NoQualLong:  identity.NoQualLong,
+		PrevState:   stateName(identity.PrevState),
+		NewState:    stateName(identity.NewState),
+		Birthday:    identity.Birthday,
+	}
+}
+
+func formatScore(point float32, flips uint32, score float32) string {
+	return fmt.Sprintf("%.3f (%v/%v)", score, point, flips)
+}
+
+func stateName(identityState state.IdentityState) string {
+	switch identityState {
+	case state.Invite:
+		return "Invite"
+	case state.Candidate:
+		return "Candidate"
+	case state.Newbie:
+		return "Newbie"
+	case state.Verified:
+		return "Verified"
+	case state.Suspended:
+		return "Suspended"
+	case state.Zombie:
+		return "Zombie"
+	case state.Killed:
+		return "Killed"
+	default:
+		return "Undefined"
+	}
+}
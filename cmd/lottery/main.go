@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/idena-network/idena-go/cmd/utils"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/ceremony"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/log"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var (
+	InputFlag = cli.StringFlag{
+		Name:  "input",
+		Usage: "Read the lottery data from the json file returned by flip_lotteryData instead of the datadir",
+	}
+	HeightFlag = cli.Uint64Flag{
+		Name:  "height",
+		Usage: "Use the state of the given block, the head is used by default",
+	}
+	AddressFlag = cli.StringFlag{
+		Name:  "address",
+		Usage: "Address of the candidate to verify",
+	}
+	ShortFlipsFlag = cli.StringFlag{
+		Name:  "short",
+		Usage: "Comma separated short session flip hashes to compare with",
+	}
+	LongFlipsFlag = cli.StringFlag{
+		Name:  "long",
+		Usage: "Comma separated long session flip hashes to compare with",
+	}
+	JsonFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result as json",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Usage = "Recompute the flip lottery of the epoch and verify flips to solve of the candidate. " +
+		"The lottery data is read either from the datadir taken during the ceremony or from the json file"
+
+	app.Flags = []cli.Flag{
+		config.DataDirFlag,
+		config.VerbosityFlag,
+		InputFlag,
+		HeightFlag,
+		AddressFlag,
+		ShortFlipsFlag,
+		LongFlipsFlag,
+		JsonFlag,
+	}
+
+	app.Action = func(context *cli.Context) error {
+		utils.SetupLogger(context.Int("verbosity"))
+
+		if !context.IsSet(AddressFlag.Name) {
+			return errors.New("address option is required")
+		}
+		address := common.HexToAddress(context.String(AddressFlag.Name))
+
+		shortFlips, err := parseCids(context.String(ShortFlipsFlag.Name))
+		if err != nil {
+			return err
+		}
+		longFlips, err := parseCids(context.String(LongFlipsFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		var data *ceremony.LotteryData
+		if context.IsSet(InputFlag.Name) {
+			data, err = readInput(context.String(InputFlag.Name))
+		} else {
+			data, err = readDataDir(context)
+		}
+		if err != nil {
+			return err
+		}
+
+		result, err := data.Verify(address, shortFlips, longFlips)
+		if err != nil {
+			return err
+		}
+
+		if context.Bool(JsonFlag.Name) {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		}
+
+		fmt.Printf("Epoch: %v, candidates: %v\n", data.Epoch, len(data.Candidates))
+		fmt.Printf("Address: %v\n", result.Address.Hex())
+		for _, author := range result.RelatedAuthors {
+			fmt.Printf("Excluded related author: %v\n", author.Hex())
+		}
+		printFlips("Short session flips", result.ShortFlips, shortFlips != nil, result.ShortMatch)
+		printFlips("Long session flips", result.LongFlips, longFlips != nil, result.LongMatch)
+		if !result.ShortMatch || !result.LongMatch {
+			return errors.New("flips to solve do not match the lottery")
+		}
+		return nil
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func printFlips(title string, flips []*ceremony.LotteryFlip, compared bool, match bool) {
+	fmt.Println()
+	switch {
+	case !compared:
+		fmt.Printf("%v:\n", title)
+	case match:
+		fmt.Printf("%v (match):\n", title)
+	default:
+		fmt.Printf("%v (MISMATCH):\n", title)
+	}
+	for _, flip := range flips {
+		text, _ := flip.Cid.MarshalText()
+		related := ""
+		if flip.Related {
+			related = " (related author)"
+		}
+		fmt.Printf("  %v by %v%v\n", string(text), flip.Author.Hex(), related)
+	}
+}
+
+func parseCids(value string) ([][]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var result [][]byte
+	for _, hash := range strings.Split(value, ",") {
+		c, err := cid.Decode(strings.TrimSpace(hash))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid flip hash %v", hash)
+		}
+		result = append(result, c.Bytes())
+	}
+	return result, nil
+}
+
+func readInput(file string) (*ceremony.LotteryData, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	// the file may contain either the lottery data or the whole rpc response
+	var response struct {
+		Result *ceremony.LotteryData `json:"result"`
+	}
+	if err := json.Unmarshal(content, &response); err == nil && response.Result != nil {
+		return response.Result, nil
+	}
+	data := new(ceremony.LotteryData)
+	if err := json.Unmarshal(content, data); err != nil {
+		return nil, errors.Wrap(err, "cannot parse lottery data")
+	}
+	return data, nil
+}
+
+func readDataDir(context *cli.Context) (*ceremony.LotteryData, error) {
+	if !context.IsSet(config.DataDirFlag.Name) {
+		return nil, errors.New("either datadir or input option is required")
+	}
+	db, err := utils.OpenDatabase(context.String(config.DataDirFlag.Name), "idenachain", 16, 16)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	head := database.NewRepo(db).ReadHead()
+	if head == nil {
+		return nil, errors.New("head is not found")
+	}
+	height := head.Height()
+	if context.IsSet(HeightFlag.Name) {
+		height = context.Uint64(HeightFlag.Name)
+	}
+	if height > head.Height() {
+		return nil, errors.Errorf("height %v is greater than the head height %v", height, head.Height())
+	}
+
+	appState := appstate.NewAppState(db, eventbus.New())
+	if err := appState.Initialize(height); err != nil {
+		return nil, errors.Wrapf(err, "cannot load state at height %v", height)
+	}
+	return ceremony.ReadLotteryData(appState, db)
+}
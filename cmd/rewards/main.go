@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/cmd/utils"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/log"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+	"math/big"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+var (
+	AddressFlag = cli.StringFlag{
+		Name:  "address",
+		Usage: "Show rewards of the given address only",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Usage = "Forecast validation rewards of the current epoch"
+
+	app.Flags = []cli.Flag{
+		config.CfgFileFlag,
+		config.DataDirFlag,
+		config.VerbosityFlag,
+		AddressFlag,
+	}
+
+	app.Action = func(context *cli.Context) error {
+		utils.SetupLogger(context.Int("verbosity"))
+
+		if !context.IsSet(config.DataDirFlag.Name) {
+			return errors.New("datadir option is required")
+		}
+
+		cfg, err := config.MakeConfigFromFile(context.String(config.CfgFileFlag.Name))
+		if err != nil {
+			return err
+		}
+
+		db, err := utils.OpenDatabase(context.String(config.DataDirFlag.Name), "idenachain", 16, 16)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		repo := database.NewRepo(db)
+
+		head := repo.ReadHead()
+		if head == nil {
+			return errors.New("head is not found")
+		}
+		appState := appstate.NewAppState(db, eventbus.New())
+		if err := appState.Initialize(head.Height()); err != nil {
+			return err
+		}
+
+		blocks := head.Height() - appState.State.EpochBlock()
+		forecast := blockchain.ForecastRewards(appState, cfg.Consensus, blockchain.EstimateValidationAuthors(appState), blocks)
+
+		var addresses []common.Address
+		if context.IsSet(AddressFlag.Name) {
+			addresses = append(addresses, common.HexToAddress(context.String(AddressFlag.Name)))
+		} else {
+			for addr := range forecast.Identities {
+				addresses = append(addresses, addr)
+			}
+			sort.Slice(addresses, func(i, j int) bool {
+				return addresses[i].Hex() < addresses[j].Hex()
+			})
+		}
+
+		fmt.Printf("Epoch: %v, blocks: %v\n", forecast.Epoch, forecast.Blocks)
+		fmt.Printf("Total reward: %v\n", format(forecast.TotalReward))
+		fmt.Printf("Validation reward: %v\n", format(forecast.ValidationReward))
+		fmt.Printf("Flips reward: %v\n", format(forecast.FlipsReward))
+		fmt.Printf("Invitations reward: %v\n", format(forecast.InvitationsReward))
+		fmt.Printf("Foundation payouts: %v\n", format(forecast.FoundationPayouts))
+		fmt.Printf("Zero wallet fund: %v\n\n", format(forecast.ZeroWalletFund))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ADDRESS\tFLIPS\tINVITES\tVALIDATION\tFLIPS REWARD\tINVITATIONS REWARD\tBALANCE\tSTAKE")
+		for _, addr := range addresses {
+			rewards, ok := forecast.Identities[addr]
+			if !ok {
+				rewards = &blockchain.IdentityRewardsForecast{}
+			}
+			var flips, invites int
+			if author, ok := forecast.Authors.GoodAuthors[addr]; ok {
+				flips = author.StrongFlips + author.WeakFlips
+				invites = author.SuccessfulInvites
+			}
+			balance, stake := new(big.Int), new(big.Int)
+			for _, part := range []*blockchain.RewardPart{rewards.Validation, rewards.Flips, rewards.Invitations} {
+				if part != nil {
+					balance.Add(balance, part.Balance)
+					stake.Add(stake, part.Stake)
+				}
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", addr.Hex(), flips, invites, formatPart(rewards.Validation),
+				formatPart(rewards.Flips), formatPart(rewards.Invitations), format(balance), format(stake))
+		}
+		return w.Flush()
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+	}
+}
+
+func format(amount *big.Int) string {
+	return blockchain.ConvertToFloat(amount).String()
+}
+
+func formatPart(part *blockchain.RewardPart) string {
+	if part == nil {
+		return "0"
+	}
+	return format(new(big.Int).Add(part.Balance, part.Stake))
+}
@@ -0,0 +1,16 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package main
+
+import (
+	"net"
+	"syscall"
+)
+
+// listen creates the signer socket, the umask makes the socket accessible by the owner only from the start
+func listen(socket string) (net.Listener, error) {
+	mask := syscall.Umask(0177)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", socket)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package main
+
+import "net"
+
+// listen creates the signer socket
+func listen(socket string) (net.Listener, error) {
+	return net.Listen("unix", socket)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/cmd/utils"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/log"
+	"github.com/idena-network/idena-go/secstore"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	KeyFlag = cli.StringFlag{
+		Name:  "key",
+		Usage: "Node key file, datadir/keystore/nodekey is used by default",
+	}
+	SocketFlag = cli.StringFlag{
+		Name:  "socket",
+		Usage: "Unix socket to listen to, the node should be started with the same --signer value",
+	}
+	ConfirmSpendsFlag = cli.BoolFlag{
+		Name:  "confirmspends",
+		Usage: "Ask for the confirmation of txs which transfer coins",
+	}
+	SpendLimitFlag = cli.StringFlag{
+		Name:  "spendlimit",
+		Usage: "Max amount in iDNA which is signed without the confirmation",
+		Value: "0",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Usage = "Keep the node key in a separate process, the node requests signatures over the unix socket"
+
+	app.Flags = []cli.Flag{
+		config.DataDirFlag,
+		config.VerbosityFlag,
+		KeyFlag,
+		SocketFlag,
+		ConfirmSpendsFlag,
+		SpendLimitFlag,
+	}
+
+	app.Action = func(context *cli.Context) error {
+		utils.SetupLogger(context.Int("verbosity"))
+
+		if !context.IsSet(SocketFlag.Name) {
+			return errors.New("socket option is required")
+		}
+		keyFile := context.String(KeyFlag.Name)
+		if keyFile == "" {
+			if !context.IsSet(config.DataDirFlag.Name) {
+				return errors.New("either key or datadir option is required")
+			}
+			keyFile = filepath.Join(context.String(config.DataDirFlag.Name), "keystore", "nodekey")
+		}
+		key, err := crypto.LoadECDSA(keyFile)
+		if err != nil {
+			return errors.Wrap(err, "cannot load the node key")
+		}
+
+		policy := secstore.NewAllowAllPolicy()
+		if context.Bool(ConfirmSpendsFlag.Name) {
+			limit, err := decimal.NewFromString(context.String(SpendLimitFlag.Name))
+			if err != nil {
+				return errors.Wrap(err, "invalid spend limit")
+			}
+			policy = secstore.NewConfirmSpendsPolicy(blockchain.ConvertToInt(limit), newConfirmation().confirm)
+		}
+
+		server, err := secstore.NewSignerServer(secstore.NewMemorySigner(crypto.FromECDSA(key)), policy)
+		if err != nil {
+			return err
+		}
+
+		socket := context.String(SocketFlag.Name)
+		if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		listener, err := listen(socket)
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		log.Info("Signer started", "address", crypto.PubkeyToAddress(key.PublicKey).Hex(), "socket", socket)
+		return server.Serve(listener)
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// confirmation asks the operator to confirm txs one by one
+type confirmation struct {
+	mutex  sync.Mutex
+	reader *bufio.Reader
+}
+
+func newConfirmation() *confirmation {
+	return &confirmation{
+		reader: bufio.NewReader(os.Stdin),
+	}
+}
+
+func (c *confirmation) confirm(tx *types.Transaction) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	to := "none"
+	if tx.To != nil {
+		to = tx.To.Hex()
+	}
+	fmt.Printf("\nSign tx type: %v, to: %v, amount: %v, max fee: %v, nonce: %v, epoch: %v? [y/N] ",
+		tx.Type, to, blockchain.ConvertToFloat(tx.AmountOrZero()), blockchain.ConvertToFloat(tx.MaxFee), tx.AccountNonce, tx.Epoch)
+	answer, err := c.reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
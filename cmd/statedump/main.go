@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/cmd/utils"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/log"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	formatJson = "json"
+	formatCsv  = "csv"
+)
+
+var (
+	HeightFlag = cli.Uint64Flag{
+		Name:  "height",
+		Usage: "Export the state of the given block, the head is used by default. Only last retained states are available",
+	}
+	FormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format: json (JSON lines) or csv",
+		Value: formatJson,
+	}
+	OutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "Output directory, accounts, identities and approved identities are written to separate files",
+		Value: ".",
+	}
+)
+
+type accountRecord struct {
+	Address common.Address  `json:"address"`
+	Balance decimal.Decimal `json:"balance"`
+	Nonce   uint32          `json:"nonce"`
+	Epoch   uint16          `json:"epoch"`
+}
+
+var accountHeader = []string{"address", "balance", "nonce", "epoch"}
+
+func (r *accountRecord) values() []string {
+	return []string{
+		r.Address.Hex(),
+		r.Balance.String(),
+		strconv.FormatUint(uint64(r.Nonce), 10),
+		strconv.FormatUint(uint64(r.Epoch), 10),
+	}
+}
+
+type identityRecord struct {
+	Address         common.Address  `json:"address"`
+	State           string          `json:"state"`
+	Stake           decimal.Decimal `json:"stake"`
+	Penalty         decimal.Decimal `json:"penalty"`
+	Birthday        uint16          `json:"birthday"`
+	Invites         uint8           `json:"invites"`
+	RequiredFlips   uint8           `json:"requiredFlips"`
+	MadeFlips       int             `json:"madeFlips"`
+	QualifiedFlips  uint32          `json:"qualifiedFlips"`
+	ShortFlipPoints float32         `json:"shortFlipPoints"`
+	Generation      uint32          `json:"generation"`
+	Code            hexutil.Bytes   `json:"code"`
+	Inviter         *common.Address `json:"inviter"`
+	Invitees        int             `json:"invitees"`
+}
+
+var identityHeader = []string{"address", "state", "stake", "penalty", "birthday", "invites", "requiredFlips", "madeFlips",
+	"qualifiedFlips", "shortFlipPoints", "generation", "code", "inviter", "invitees"}
+
+func (r *identityRecord) values() []string {
+	inviter := ""
+	if r.Inviter != nil {
+		inviter = r.Inviter.Hex()
+	}
+	return []string{
+		r.Address.Hex(),
+		r.State,
+		r.Stake.String(),
+		r.Penalty.String(),
+		strconv.FormatUint(uint64(r.Birthday), 10),
+		strconv.FormatUint(uint64(r.Invites), 10),
+		strconv.FormatUint(uint64(r.RequiredFlips), 10),
+		strconv.Itoa(r.MadeFlips),
+		strconv.FormatUint(uint64(r.QualifiedFlips), 10),
+		strconv.FormatFloat(float64(r.ShortFlipPoints), 'f', -1, 32),
+		strconv.FormatUint(uint64(r.Generation), 10),
+		r.Code.String(),
+		inviter,
+		strconv.Itoa(r.Invitees),
+	}
+}
+
+type approvedIdentityRecord struct {
+	Address  common.Address `json:"address"`
+	Approved bool           `json:"approved"`
+	Online   bool           `json:"online"`
+}
+
+var approvedIdentityHeader = []string{"address", "approved", "online"}
+
+func (r *approvedIdentityRecord) values() []string {
+	return []string{
+		r.Address.Hex(),
+		strconv.FormatBool(r.Approved),
+		strconv.FormatBool(r.Online),
+	}
+}
+
+type record interface {
+	values() []string
+}
+
+// recordWriter streams records to the file, so the whole state is never kept in memory
+type recordWriter struct {
+	file    *os.File
+	buf     *bufio.Writer
+	json    *json.Encoder
+	csv     *csv.Writer
+	records int
+}
+
+func newRecordWriter(dir string, name string, format string, header []string) (*recordWriter, error) {
+	ext := ".jsonl"
+	if format == formatCsv {
+		ext = ".csv"
+	}
+	file, err := os.Create(filepath.Join(dir, name+ext))
+	if err != nil {
+		return nil, err
+	}
+	w := &recordWriter{
+		file: file,
+		buf:  bufio.NewWriter(file),
+	}
+	if format == formatCsv {
+		w.csv = csv.NewWriter(w.buf)
+		if err := w.csv.Write(header); err != nil {
+			file.Close()
+			return nil, err
+		}
+	} else {
+		w.json = json.NewEncoder(w.buf)
+	}
+	return w, nil
+}
+
+func (w *recordWriter) write(r record) error {
+	w.records++
+	if w.csv != nil {
+		return w.csv.Write(r.values())
+	}
+	return w.json.Encode(r)
+}
+
+func (w *recordWriter) close() error {
+	defer w.file.Close()
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Usage = "Export accounts, identities and approved identities of the state at the given height to JSON lines or CSV"
+
+	app.Flags = []cli.Flag{
+		config.DataDirFlag,
+		config.VerbosityFlag,
+		HeightFlag,
+		FormatFlag,
+		OutputFlag,
+	}
+
+	app.Action = func(context *cli.Context) error {
+		utils.SetupLogger(context.Int("verbosity"))
+
+		if !context.IsSet(config.DataDirFlag.Name) {
+			return errors.New("datadir option is required")
+		}
+		format := context.String(FormatFlag.Name)
+		if format != formatJson && format != formatCsv {
+			return errors.Errorf("unknown format %v, expected %v or %v", format, formatJson, formatCsv)
+		}
+		output := context.String(OutputFlag.Name)
+		if err := os.MkdirAll(output, 0700); err != nil {
+			return err
+		}
+
+		db, err := utils.OpenDatabase(context.String(config.DataDirFlag.Name), "idenachain", 16, 16)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		head := database.NewRepo(db).ReadHead()
+		if head == nil {
+			return errors.New("head is not found")
+		}
+		height := head.Height()
+		if context.IsSet(HeightFlag.Name) {
+			height = context.Uint64(HeightFlag.Name)
+		}
+		if height > head.Height() {
+			return errors.Errorf("height %v is greater than the head height %v", height, head.Height())
+		}
+
+		appState := appstate.NewAppState(db, eventbus.New())
+		if err := appState.Initialize(height); err != nil {
+			return errors.Wrapf(err, "state at height %v is not retained, only last %v states are kept", height, state.MaxSavedStatesCount)
+		}
+
+		if err := exportAccounts(appState, output, format); err != nil {
+			return errors.Wrap(err, "cannot export accounts")
+		}
+		if err := exportIdentities(appState, output, format); err != nil {
+			return errors.Wrap(err, "cannot export identities")
+		}
+		if err := exportApprovedIdentities(appState, output, format); err != nil {
+			return errors.Wrap(err, "cannot export approved identities")
+		}
+		log.Info("State exported", "height", height, "epoch", appState.State.Epoch(), "output", output)
+		return nil
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func exportAccounts(appState *appstate.AppState, dir string, format string) error {
+	w, err := newRecordWriter(dir, "accounts", format, accountHeader)
+	if err != nil {
+		return err
+	}
+	var writeErr error
+	appState.State.IterateAccounts(func(key []byte, value []byte) bool {
+		if key == nil {
+			return true
+		}
+		var data state.Account
+		if writeErr = rlp.DecodeBytes(value, &data); writeErr != nil {
+			return true
+		}
+		writeErr = w.write(&accountRecord{
+			Address: common.BytesToAddress(key[1:]),
+			Balance: blockchain.ConvertToFloat(data.Balance),
+			Nonce:   data.Nonce,
+			Epoch:   data.Epoch,
+		})
+		return writeErr != nil
+	})
+	return finish(w, "accounts", writeErr)
+}
+
+func exportIdentities(appState *appstate.AppState, dir string, format string) error {
+	w, err := newRecordWriter(dir, "identities", format, identityHeader)
+	if err != nil {
+		return err
+	}
+	var writeErr error
+	appState.State.IterateIdentities(func(key []byte, value []byte) bool {
+		if key == nil {
+			return true
+		}
+		var data state.Identity
+		if writeErr = rlp.DecodeBytes(value, &data); writeErr != nil {
+			return true
+		}
+		item := &identityRecord{
+			Address:         common.BytesToAddress(key[1:]),
+			State:           state.IdentityStateName(data.State),
+			Stake:           blockchain.ConvertToFloat(data.Stake),
+			Penalty:         blockchain.ConvertToFloat(data.Penalty),
+			Birthday:        data.Birthday,
+			Invites:         data.Invites,
+			RequiredFlips:   data.RequiredFlips,
+			MadeFlips:       len(data.Flips),
+			QualifiedFlips:  data.QualifiedFlips,
+			ShortFlipPoints: data.GetShortFlipPoints(),
+			Generation:      data.Generation,
+			Code:            data.Code,
+			Invitees:        len(data.Invitees),
+		}
+		if data.Inviter != nil {
+			item.Inviter = &data.Inviter.Address
+		}
+		writeErr = w.write(item)
+		return writeErr != nil
+	})
+	return finish(w, "identities", writeErr)
+}
+
+func exportApprovedIdentities(appState *appstate.AppState, dir string, format string) error {
+	w, err := newRecordWriter(dir, "approved", format, approvedIdentityHeader)
+	if err != nil {
+		return err
+	}
+	var writeErr error
+	appState.IdentityState.IterateIdentities(func(key []byte, value []byte) bool {
+		if key == nil {
+			return true
+		}
+		var data state.ApprovedIdentity
+		if writeErr = rlp.DecodeBytes(value, &data); writeErr != nil {
+			return true
+		}
+		writeErr = w.write(&approvedIdentityRecord{
+			Address:  common.BytesToAddress(key[1:]),
+			Approved: data.Approved,
+			Online:   data.Online,
+		})
+		return writeErr != nil
+	})
+	return finish(w, "approved identities", writeErr)
+}
+
+func finish(w *recordWriter, name string, writeErr error) error {
+	if err := w.close(); err != nil && writeErr == nil {
+		writeErr = err
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	log.Info("Exported "+name, "count", w.records)
+	return nil
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/idena-network/idena-go/blockchain"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	genesisConfigFile   = "genesis.json"
+	predefinedStateFile = "predefined-state.rlp"
+)
+
+var (
+	InputFlag = cli.StringFlag{
+		Name:  "input",
+		Usage: "JSON or YAML description of the initial network state",
+	}
+
+	buildCommand = cli.Command{
+		Name:   "build",
+		Usage:  "Build the predefined state of a private network and the config which makes nodes start from it",
+		Flags:  []cli.Flag{InputFlag},
+		Action: build,
+	}
+
+	identityStates = map[string]state.IdentityState{
+		"undefined": state.Undefined,
+		"invite":    state.Invite,
+		"candidate": state.Candidate,
+		"verified":  state.Verified,
+		"suspended": state.Suspended,
+		"killed":    state.Killed,
+		"zombie":    state.Zombie,
+		"newbie":    state.Newbie,
+	}
+)
+
+type networkDescription struct {
+	GodAddress        string                `json:"godAddress" yaml:"godAddress"`
+	FirstCeremonyTime int64                 `json:"firstCeremonyTime" yaml:"firstCeremonyTime"`
+	FeePerByte        string                `json:"feePerByte" yaml:"feePerByte"`
+	Block             uint64                `json:"block" yaml:"block"`
+	Seed              string                `json:"seed" yaml:"seed"`
+	Accounts          []accountDescription  `json:"accounts" yaml:"accounts"`
+	Identities        []identityDescription `json:"identities" yaml:"identities"`
+}
+
+type accountDescription struct {
+	Address string `json:"address" yaml:"address"`
+	Balance string `json:"balance" yaml:"balance"`
+	Nonce   uint32 `json:"nonce" yaml:"nonce"`
+}
+
+type identityDescription struct {
+	Address       string `json:"address" yaml:"address"`
+	State         string `json:"state" yaml:"state"`
+	Stake         string `json:"stake" yaml:"stake"`
+	Birthday      uint16 `json:"birthday" yaml:"birthday"`
+	Invites       uint8  `json:"invites" yaml:"invites"`
+	RequiredFlips uint8  `json:"requiredFlips" yaml:"requiredFlips"`
+	PubKey        string `json:"pubKey" yaml:"pubKey"`
+	Online        bool   `json:"online" yaml:"online"`
+}
+
+// genesisConfig is merged into the node config file, it describes the same genesis as the predefined state
+type genesisConfig struct {
+	GenesisConf genesisConf
+}
+
+type genesisConf struct {
+	GodAddress        common.Address
+	FirstCeremonyTime int64
+	FeePerByte        *big.Int `json:",omitempty"`
+	PredefinedState   string
+}
+
+func build(context *cli.Context) error {
+	if !context.IsSet(InputFlag.Name) {
+		return errors.New("input option is required")
+	}
+	desc, err := readNetworkDescription(context.String(InputFlag.Name))
+	if err != nil {
+		return err
+	}
+	predefinedState, err := buildPredefinedState(desc)
+	if err != nil {
+		return err
+	}
+	statePath, err := filepath.Abs(predefinedStateFile)
+	if err != nil {
+		return err
+	}
+	data, err := rlp.EncodeToBytes(predefinedState)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(statePath, data, 0644); err != nil {
+		return err
+	}
+	if data, err = json.MarshalIndent(buildGenesisConfig(predefinedState, statePath), "", "  "); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(genesisConfigFile, data, 0644)
+}
+
+func buildGenesisConfig(predefinedState *state.PredefinedState, statePath string) *genesisConfig {
+	return &genesisConfig{
+		GenesisConf: genesisConf{
+			GodAddress:        predefinedState.Global.GodAddress,
+			FirstCeremonyTime: predefinedState.Global.NextValidationTime.Int64(),
+			FeePerByte:        predefinedState.Global.FeePerByte,
+			PredefinedState:   statePath,
+		},
+	}
+}
+
+func readNetworkDescription(path string) (*networkDescription, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Errorf("description file cannot be read, path: %v", path)
+	}
+	desc := new(networkDescription)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, desc)
+	default:
+		err = json.Unmarshal(data, desc)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse description file, path: %v", path)
+	}
+	return desc, nil
+}
+
+func buildPredefinedState(desc *networkDescription) (*state.PredefinedState, error) {
+	if !common.IsHexAddress(desc.GodAddress) {
+		return nil, errors.Errorf("invalid god address %v", desc.GodAddress)
+	}
+	godAddress := common.HexToAddress(desc.GodAddress)
+
+	block := desc.Block
+	if block == 0 {
+		block = 1
+	}
+
+	var seed types.Seed
+	if desc.Seed != "" {
+		b, err := hex.DecodeString(strings.TrimPrefix(desc.Seed, "0x"))
+		if err != nil || len(b) != len(seed) {
+			return nil, errors.Errorf("invalid seed %v", desc.Seed)
+		}
+		copy(seed[:], b)
+	} else {
+		seed = types.Seed(crypto.Keccak256Hash(godAddress.Bytes()))
+	}
+
+	var feePerByte *big.Int
+	if desc.FeePerByte != "" {
+		var ok bool
+		if feePerByte, ok = new(big.Int).SetString(desc.FeePerByte, 10); !ok {
+			return nil, errors.Errorf("invalid fee per byte %v", desc.FeePerByte)
+		}
+	}
+
+	predefinedState := &state.PredefinedState{
+		Block: block,
+		Seed:  seed,
+		Global: state.StateGlobal{
+			NextValidationTime: big.NewInt(desc.FirstCeremonyTime),
+			GodAddress:         godAddress,
+			WordsSeed:          seed,
+			FeePerByte:         feePerByte,
+		},
+	}
+
+	for _, item := range desc.Accounts {
+		if !common.IsHexAddress(item.Address) {
+			return nil, errors.Errorf("invalid account address %v", item.Address)
+		}
+		addr := common.HexToAddress(item.Address)
+		balance, err := parseAmount(item.Balance)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid balance of %v", item.Address)
+		}
+		predefinedState.Accounts = append(predefinedState.Accounts, &state.StateAccount{
+			Address: addr,
+			Balance: balance,
+			Nonce:   item.Nonce,
+		})
+	}
+
+	for _, item := range desc.Identities {
+		if !common.IsHexAddress(item.Address) {
+			return nil, errors.Errorf("invalid identity address %v", item.Address)
+		}
+		addr := common.HexToAddress(item.Address)
+		identityState, ok := identityStates[strings.ToLower(item.State)]
+		if !ok {
+			return nil, errors.Errorf("unknown state %v of %v", item.State, item.Address)
+		}
+		stake, err := parseAmount(item.Stake)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid stake of %v", item.Address)
+		}
+		var pubKey []byte
+		if item.PubKey != "" {
+			if pubKey, err = hex.DecodeString(strings.TrimPrefix(item.PubKey, "0x")); err != nil {
+				return nil, errors.Errorf("invalid public key of %v", item.Address)
+			}
+		}
+		predefinedState.Identities = append(predefinedState.Identities, &state.StateIdentity{
+			Address:       addr,
+			State:         identityState,
+			Stake:         stake,
+			Birthday:      item.Birthday,
+			Invites:       item.Invites,
+			RequiredFlips: item.RequiredFlips,
+			PubKey:        pubKey,
+		})
+		if identityState == state.Verified || identityState == state.Newbie {
+			predefinedState.ApprovedIdentities = append(predefinedState.ApprovedIdentities, &state.StateApprovedIdentity{
+				Address:  addr,
+				Approved: true,
+				Online:   item.Online,
+			})
+		}
+	}
+
+	return predefinedState, nil
+}
+
+func parseAmount(value string) (*big.Int, error) {
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() < 0 {
+		return nil, errors.New("amount should not be negative")
+	}
+	return blockchain.ConvertToInt(amount), nil
+}
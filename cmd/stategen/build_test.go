@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestBuildPredefinedState(t *testing.T) {
+	godAddress := "0x0000000000000000000000000000000000000001"
+	addr := "0x0000000000000000000000000000000000000002"
+	pubKey := "0x0102"
+	seed := "0x0000000000000000000000000000000000000000000000000000000000000003"
+	oneDna := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+	cases := []struct {
+		name   string
+		desc   networkDescription
+		err    bool
+		verify func(t *testing.T, s *state.PredefinedState)
+	}{
+		{
+			name: "defaults",
+			desc: networkDescription{GodAddress: godAddress},
+			verify: func(t *testing.T, s *state.PredefinedState) {
+				require.Equal(t, uint64(1), s.Block)
+				require.Equal(t, common.HexToAddress(godAddress), s.Global.GodAddress)
+				require.Equal(t, s.Seed, s.Global.WordsSeed)
+				require.Nil(t, s.Global.FeePerByte)
+			},
+		},
+		{
+			name: "global params",
+			desc: networkDescription{GodAddress: godAddress, Block: 10, FirstCeremonyTime: 100, FeePerByte: "5", Seed: seed},
+			verify: func(t *testing.T, s *state.PredefinedState) {
+				require.Equal(t, uint64(10), s.Block)
+				require.Equal(t, byte(0x3), s.Seed[31])
+				require.Equal(t, big.NewInt(100), s.Global.NextValidationTime)
+				require.Equal(t, big.NewInt(5), s.Global.FeePerByte)
+			},
+		},
+		{
+			name: "account",
+			desc: networkDescription{GodAddress: godAddress, Accounts: []accountDescription{
+				{Address: addr, Balance: "1.5", Nonce: 3},
+			}},
+			verify: func(t *testing.T, s *state.PredefinedState) {
+				require.Len(t, s.Accounts, 1)
+				require.Equal(t, new(big.Int).Div(new(big.Int).Mul(oneDna, big.NewInt(3)), big.NewInt(2)), s.Accounts[0].Balance)
+				require.Equal(t, uint32(3), s.Accounts[0].Nonce)
+			},
+		},
+		{
+			name: "verified identity is approved",
+			desc: networkDescription{GodAddress: godAddress, Identities: []identityDescription{
+				{Address: addr, State: "Verified", Stake: "2", Birthday: 4, Invites: 1, RequiredFlips: 3, PubKey: pubKey, Online: true},
+			}},
+			verify: func(t *testing.T, s *state.PredefinedState) {
+				require.Len(t, s.Identities, 1)
+				identity := s.Identities[0]
+				require.Equal(t, state.Verified, identity.State)
+				require.Equal(t, new(big.Int).Mul(oneDna, big.NewInt(2)), identity.Stake)
+				require.Equal(t, uint16(4), identity.Birthday)
+				require.Equal(t, uint8(1), identity.Invites)
+				require.Equal(t, uint8(3), identity.RequiredFlips)
+				require.Equal(t, []byte{0x1, 0x2}, identity.PubKey)
+				require.Len(t, s.ApprovedIdentities, 1)
+				require.True(t, s.ApprovedIdentities[0].Online)
+			},
+		},
+		{
+			name: "newbie is approved, candidate is not",
+			desc: networkDescription{GodAddress: godAddress, Identities: []identityDescription{
+				{Address: addr, State: "newbie"},
+				{Address: godAddress, State: "candidate"},
+			}},
+			verify: func(t *testing.T, s *state.PredefinedState) {
+				require.Len(t, s.Identities, 2)
+				require.Len(t, s.ApprovedIdentities, 1)
+				require.Equal(t, common.HexToAddress(addr), s.ApprovedIdentities[0].Address)
+			},
+		},
+		{name: "invalid god address", desc: networkDescription{GodAddress: "0x1"}, err: true},
+		{name: "invalid seed", desc: networkDescription{GodAddress: godAddress, Seed: "0x01"}, err: true},
+		{name: "invalid fee", desc: networkDescription{GodAddress: godAddress, FeePerByte: "x"}, err: true},
+		{name: "negative balance", desc: networkDescription{GodAddress: godAddress, Accounts: []accountDescription{
+			{Address: addr, Balance: "-1"},
+		}}, err: true},
+		{name: "unknown state", desc: networkDescription{GodAddress: godAddress, Identities: []identityDescription{
+			{Address: addr, State: "human"},
+		}}, err: true},
+		{name: "invalid pub key", desc: networkDescription{GodAddress: godAddress, Identities: []identityDescription{
+			{Address: addr, State: "verified", PubKey: "0xzz"},
+		}}, err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := buildPredefinedState(&c.desc)
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			c.verify(t, s)
+		})
+	}
+}
+
+func TestBuildGenesisConfig(t *testing.T) {
+	godAddress := "0x0000000000000000000000000000000000000001"
+	s, err := buildPredefinedState(&networkDescription{GodAddress: godAddress, FirstCeremonyTime: 100, FeePerByte: "5"})
+	require.NoError(t, err)
+
+	conf := buildGenesisConfig(s, "/tmp/state.rlp")
+	require.Equal(t, common.HexToAddress(godAddress), conf.GenesisConf.GodAddress)
+	require.Equal(t, int64(100), conf.GenesisConf.FirstCeremonyTime)
+	require.Equal(t, big.NewInt(5), conf.GenesisConf.FeePerByte)
+	require.Equal(t, "/tmp/state.rlp", conf.GenesisConf.PredefinedState)
+
+	data, err := json.Marshal(conf)
+	require.NoError(t, err)
+	nodeConf := &config.Config{GenesisConf: &config.GenesisConf{}}
+	require.NoError(t, json.Unmarshal(data, nodeConf))
+	require.Equal(t, conf.GenesisConf.GodAddress, nodeConf.GenesisConf.GodAddress)
+	require.Equal(t, conf.GenesisConf.FirstCeremonyTime, nodeConf.GenesisConf.FirstCeremonyTime)
+	require.Equal(t, conf.GenesisConf.FeePerByte, nodeConf.GenesisConf.FeePerByte)
+	require.Equal(t, conf.GenesisConf.PredefinedState, nodeConf.GenesisConf.PredefinedState)
+}
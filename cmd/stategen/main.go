@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/idena-network/idena-go/cmd/utils"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/eventbus"
 	"github.com/idena-network/idena-go/config"
@@ -14,11 +15,6 @@ import (
 	"gopkg.in/urfave/cli.v1"
 	"os"
 	"path/filepath"
-	"runtime"
-
-	"github.com/syndtr/goleveldb/leveldb/filter"
-	"github.com/syndtr/goleveldb/leveldb/opt"
-	"github.com/tendermint/tm-db"
 )
 
 func main() {
@@ -30,21 +26,13 @@ func main() {
 	}
 
 	app.Action = func(context *cli.Context) error {
-		logLvl := log.Lvl(context.Int("verbosity"))
-
-		var handler log.Handler
-		if runtime.GOOS == "windows" {
-			handler = log.LvlFilterHandler(logLvl, log.StreamHandler(os.Stdout, log.LogfmtFormat()))
-		} else {
-			handler = log.LvlFilterHandler(logLvl, log.StreamHandler(os.Stderr, log.TerminalFormat(true)))
-		}
-		log.Root().SetHandler(handler)
+		utils.SetupLogger(context.Int("verbosity"))
 
 		if !context.IsSet(config.DataDirFlag.Name) {
 			return errors.New("datadir option is required")
 		}
 
-		db, err := OpenDatabase(context.String(config.DataDirFlag.Name), "idenachain", 16, 16)
+		db, err := utils.OpenDatabase(context.String(config.DataDirFlag.Name), "idenachain", 16, 16)
 		if err != nil {
 			return err
 		}
@@ -158,26 +146,11 @@ func main() {
 			})
 			return false
 		})
-		file, err := os.Create("stategen.out")
-		if err != nil {
-			return err
-		}
-
-		if err := rlp.Encode(file, snapshot); err != nil {
-			return err
-		}
-		file.Close()
-
-		err = bindata.Translate(&bindata.Config{
-			Input: []bindata.InputConfig{{
-				Path:      filepath.Clean("stategen.out"),
-				Recursive: false,
-			}},
-			Package: "blockchain",
-			Output:  "bindata.go",
-		})
+		return writePredefinedState(&snapshot)
+	}
 
-		return nil
+	app.Commands = []cli.Command{
+		buildCommand,
 	}
 
 	err := app.Run(os.Args)
@@ -186,11 +159,24 @@ func main() {
 	}
 }
 
-func OpenDatabase(datadir string, name string, cache int, handles int) (db.DB, error) {
-	return db.NewGoLevelDBWithOpts(name, datadir, &opt.Options{
-		OpenFilesCacheCapacity: handles,
-		BlockCacheCapacity:     cache / 2 * opt.MiB,
-		WriteBuffer:            cache / 4 * opt.MiB,
-		Filter:                 filter.NewBloomFilter(10),
+func writePredefinedState(snapshot *state.PredefinedState) error {
+	file, err := os.Create("stategen.out")
+	if err != nil {
+		return err
+	}
+
+	if err := rlp.Encode(file, snapshot); err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	return bindata.Translate(&bindata.Config{
+		Input: []bindata.InputConfig{{
+			Path:      filepath.Clean("stategen.out"),
+			Recursive: false,
+		}},
+		Package: "blockchain",
+		Output:  "bindata.go",
 	})
 }
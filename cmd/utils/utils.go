@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"github.com/idena-network/idena-go/log"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/tendermint/tm-db"
+	"os"
+	"runtime"
+)
+
+// SetupLogger sets the root log handler of the command line tools
+func SetupLogger(verbosity int) {
+	logLvl := log.Lvl(verbosity)
+
+	var handler log.Handler
+	if runtime.GOOS == "windows" {
+		handler = log.LvlFilterHandler(logLvl, log.StreamHandler(os.Stdout, log.LogfmtFormat()))
+	} else {
+		handler = log.LvlFilterHandler(logLvl, log.StreamHandler(os.Stderr, log.TerminalFormat(true)))
+	}
+	log.Root().SetHandler(handler)
+}
+
+// OpenDatabase opens the node database from the datadir
+func OpenDatabase(datadir string, name string, cache int, handles int) (db.DB, error) {
+	return db.NewGoLevelDBWithOpts(name, datadir, &opt.Options{
+		OpenFilesCacheCapacity: handles,
+		BlockCacheCapacity:     cache / 2 * opt.MiB,
+		WriteBuffer:            cache / 4 * opt.MiB,
+		Filter:                 filter.NewBloomFilter(10),
+	})
+}
@@ -0,0 +1,14 @@
+package config
+
+type ArchiveConfig struct {
+	// Enabled turns on saving own flips, answers and flip qualification before the epoch data is cleared
+	Enabled bool
+	// Dir is a directory within the datadir to keep archived epochs
+	Dir string
+}
+
+func GetDefaultArchiveConfig() *ArchiveConfig {
+	return &ArchiveConfig{
+		Dir: "archive",
+	}
+}
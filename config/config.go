@@ -29,6 +29,7 @@ const (
 type Config struct {
 	DataDir          string
 	Network          uint32
+	NetworkProfile   string
 	Consensus        *ConsensusConf
 	P2P              *p2p.Config
 	RPC              *rpc.Config
@@ -38,6 +39,11 @@ type Config struct {
 	Sync             *SyncConfig
 	OfflineDetection *OfflineDetectionConfig
 	Blockchain       *BlockchainConfig
+	Mempool          *MempoolConfig
+	Archive          *ArchiveConfig
+	Stats            *StatsConfig
+	StateDiff        *StateDiffConfig
+	Signer           *SignerConfig
 }
 
 func (c *Config) ProvideNodeKey(key string, password string, withBackup bool) error {
@@ -125,6 +131,25 @@ func (c *Config) NodeDB() string {
 	return filepath.Join(c.DataDir, "nodes")
 }
 
+// MempoolJournal returns the path to the mempool journal, empty path disables journaling
+func (c *Config) MempoolJournal() string {
+	if c.DataDir == "" || c.Mempool == nil || c.Mempool.Journal == "" {
+		return ""
+	}
+	return filepath.Join(c.DataDir, c.Mempool.Journal)
+}
+
+// ArchiveDir returns the path to the directory of archived epochs
+func (c *Config) ArchiveDir() string {
+	if c.Archive == nil || c.Archive.Dir == "" {
+		return ""
+	}
+	if filepath.IsAbs(c.Archive.Dir) {
+		return c.Archive.Dir
+	}
+	return filepath.Join(c.DataDir, c.Archive.Dir)
+}
+
 func (c *Config) KeyStoreDataDir() (string, error) {
 	instanceDir := filepath.Join(c.DataDir, "keystore")
 	if err := os.MkdirAll(instanceDir, 0700); err != nil {
@@ -179,7 +204,11 @@ func MakeMobileConfig(path string, cfg string) (*Config, error) {
 }
 
 func MakeConfig(ctx *cli.Context) (*Config, error) {
-	cfg, err := MakeConfigFromFile(ctx.String(CfgFileFlag.Name))
+	networkProfile := DefaultNetworkProfile
+	if ctx.IsSet(NetworkFlag.Name) {
+		networkProfile = ctx.String(NetworkFlag.Name)
+	}
+	cfg, err := makeConfigFromFile(ctx.String(CfgFileFlag.Name), networkProfile)
 	if err != nil {
 		return nil, err
 	}
@@ -217,7 +246,14 @@ func applyProfile(ctx *cli.Context, cfg *Config) {
 }
 
 func MakeConfigFromFile(file string) (*Config, error) {
+	return makeConfigFromFile(file, DefaultNetworkProfile)
+}
+
+func makeConfigFromFile(file string, networkProfile string) (*Config, error) {
 	cfg := getDefaultConfig(DefaultDataDir)
+	if err := applyNetworkProfile(cfg, networkProfile); err != nil {
+		return nil, err
+	}
 	if file != "" {
 		if err := loadConfig(file, cfg); err != nil {
 			log.Error(err.Error())
@@ -235,8 +271,9 @@ func getDefaultConfig(dataDir string) *Config {
 	}
 
 	return &Config{
-		DataDir: dataDir,
-		Network: 0x1, // testnet
+		DataDir:        dataDir,
+		Network:        0x1, // testnet
+		NetworkProfile: DefaultNetworkProfile,
 		P2P: &p2p.Config{
 			ListenAddr:     fmt.Sprintf(":%d", DefaultPort),
 			MaxPeers:       DefaultMaxPeers,
@@ -265,6 +302,11 @@ func getDefaultConfig(dataDir string) *Config {
 			StoreCertRange: DefaultStoreCertRange,
 			BurnTxRange:    DefaultBurntTxRange,
 		},
+		Mempool:   GetDefaultMempoolConfig(),
+		Archive:   GetDefaultArchiveConfig(),
+		Stats:     GetDefaultStatsConfig(),
+		StateDiff: GetDefaultStateDiffConfig(),
+		Signer:    GetDefaultSignerConfig(),
 	}
 }
 
@@ -280,6 +322,34 @@ func applyFlags(ctx *cli.Context, cfg *Config) {
 	applyIpfsFlags(ctx, cfg)
 	applyValidationFlags(ctx, cfg)
 	applySyncFlags(ctx, cfg)
+	applyArchiveFlags(ctx, cfg)
+	applyStatsFlags(ctx, cfg)
+	applyStateDiffFlags(ctx, cfg)
+	applySignerFlags(ctx, cfg)
+}
+
+func applyArchiveFlags(ctx *cli.Context, cfg *Config) {
+	if ctx.IsSet(ArchiveFlag.Name) {
+		cfg.Archive.Enabled = ctx.Bool(ArchiveFlag.Name)
+	}
+}
+
+func applyStatsFlags(ctx *cli.Context, cfg *Config) {
+	if ctx.IsSet(StatsFlag.Name) {
+		cfg.Stats.Enabled = ctx.Bool(StatsFlag.Name)
+	}
+}
+
+func applyStateDiffFlags(ctx *cli.Context, cfg *Config) {
+	if ctx.IsSet(StateDiffFlag.Name) {
+		cfg.StateDiff.Enabled = ctx.Bool(StateDiffFlag.Name)
+	}
+}
+
+func applySignerFlags(ctx *cli.Context, cfg *Config) {
+	if ctx.IsSet(SignerFlag.Name) {
+		cfg.Signer.Socket = ctx.String(SignerFlag.Name)
+	}
 }
 
 func applySyncFlags(ctx *cli.Context, cfg *Config) {
@@ -289,6 +359,9 @@ func applySyncFlags(ctx *cli.Context, cfg *Config) {
 	if ctx.IsSet(ForceFullSyncFlag.Name) {
 		cfg.Sync.ForceFullSync = ctx.Uint64(ForceFullSyncFlag.Name)
 	}
+	if ctx.IsSet(SnapshotV2Flag.Name) {
+		cfg.Sync.SnapshotV2 = ctx.Bool(SnapshotV2Flag.Name)
+	}
 }
 
 func applyP2PFlags(ctx *cli.Context, cfg *Config) {
@@ -329,6 +402,15 @@ func applyRpcFlags(ctx *cli.Context, cfg *Config) {
 	if ctx.IsSet(RpcPortFlag.Name) {
 		cfg.RPC.HTTPPort = ctx.Int(RpcPortFlag.Name)
 	}
+	if ctx.IsSet(WsHostFlag.Name) {
+		cfg.RPC.WSHost = ctx.String(WsHostFlag.Name)
+	}
+	if ctx.IsSet(WsPortFlag.Name) {
+		cfg.RPC.WSPort = ctx.Int(WsPortFlag.Name)
+		if cfg.RPC.WSHost == "" {
+			cfg.RPC.WSHost = cfg.RPC.HTTPHost
+		}
+	}
 	if ctx.IsSet(ApiKeyFlag.Name) {
 		cfg.RPC.APIKey = ctx.String(ApiKeyFlag.Name)
 		if cfg.RPC.APIKey != "" {
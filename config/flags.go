@@ -49,6 +49,14 @@ var (
 		Name:  "rpcport",
 		Usage: "RPC listening port",
 	}
+	WsHostFlag = cli.StringFlag{
+		Name:  "wsaddr",
+		Usage: "Websocket RPC listening address, websocket RPC is disabled if not set",
+	}
+	WsPortFlag = cli.IntFlag{
+		Name:  "wsport",
+		Usage: "Websocket RPC listening port",
+	}
 	BootNodeFlag = cli.StringFlag{
 		Name:  "bootnode",
 		Usage: "Bootstrap node url",
@@ -76,7 +84,7 @@ var (
 	}
 	GodAddressFlag = cli.StringFlag{
 		Name:  "godaddress",
-		Usage: "Idena god address",
+		Usage: "Idena god address, the node address is used by default for devnet",
 	}
 	CeremonyTimeFlag = cli.Int64Flag{
 		Name:  "ceremonytime",
@@ -94,6 +102,10 @@ var (
 		Name:  "forcefullsync",
 		Usage: "Force full sync on last blocks",
 	}
+	SnapshotV2Flag = cli.BoolFlag{
+		Name:  "snapshotv2",
+		Usage: "Publish chunked v2 snapshots for fast sync",
+	}
 	ProfileFlag = cli.StringFlag{
 		Name:  "profile",
 		Usage: "Configuration profile",
@@ -106,4 +118,25 @@ var (
 		Name:  "apikey",
 		Usage: "Set RPC api key",
 	}
+	ArchiveFlag = cli.BoolFlag{
+		Name:  "archive",
+		Usage: "Archive own flips and answers of every epoch",
+	}
+	StatsFlag = cli.BoolFlag{
+		Name:  "stats",
+		Usage: "Collect rewards and validation stats of every epoch",
+	}
+	StateDiffFlag = cli.BoolFlag{
+		Name:  "statediff",
+		Usage: "Save account and identity changes of every block for bcn_stateDiff",
+	}
+	SignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "Unix socket of the external signer which keeps the node key",
+	}
+	NetworkFlag = cli.StringFlag{
+		Name:  "network",
+		Usage: "Network profile (testnet, devnet, custom)",
+		Value: DefaultNetworkProfile,
+	}
 )
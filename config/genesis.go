@@ -15,4 +15,10 @@ type GenesisConf struct {
 	Alloc             map[common.Address]GenesisAllocation
 	GodAddress        common.Address
 	FirstCeremonyTime int64
+	FeePerByte        *big.Int
+	// GodBalance is given to the god address at the genesis
+	GodBalance *big.Int
+	// PredefinedState is the path to the rlp encoded state built by stategen, the genesis block is created from it
+	// instead of the bundled testnet state, so a private network does not require a rebuild
+	PredefinedState string
 }
@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+type MempoolConfig struct {
+	// TotalTxLimit and AddrTxLimit restrict the number of pending txs, non-positive value disables the limit
+	TotalTxLimit int
+	AddrTxLimit  int
+	// TxPriceBump is the minimal increase (in percents) of MaxFee and Tips required to replace a pending tx
+	TxPriceBump int
+	// Journal is a file name within the datadir to keep pending txs between restarts, empty value disables journaling
+	Journal string
+	// RejournalInterval is the period of the journal compaction, non-positive value disables periodic compaction
+	RejournalInterval time.Duration
+	// TxLifetime is the maximum time a tx may wait for mining, zero value disables expiration
+	TxLifetime time.Duration
+	// RebroadcastInterval is the period of broadcasting own pending txs again, zero value disables rebroadcasting
+	RebroadcastInterval time.Duration
+	// ResignOwnTxs allows to replace own txs by copies with a higher max fee when the network fee exceeds their cap
+	ResignOwnTxs bool
+}
+
+func GetDefaultMempoolConfig() *MempoolConfig {
+	return &MempoolConfig{
+		TotalTxLimit:        100000,
+		AddrTxLimit:         1000,
+		TxPriceBump:         10,
+		Journal:             "mempool.rlp",
+		RejournalInterval:   time.Hour,
+		TxLifetime:          3 * time.Hour,
+		RebroadcastInterval: 10 * time.Minute,
+	}
+}
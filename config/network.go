@@ -0,0 +1,197 @@
+package config
+
+import (
+	"encoding/json"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/log"
+	"github.com/idena-network/idena-go/p2p/enode"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	TestnetProfile = "testnet"
+	DevnetProfile  = "devnet"
+	CustomProfile  = "custom"
+
+	DefaultNetworkProfile = TestnetProfile
+	DevnetSwarmKey        = "a49d9dbff3bd65a59a7b9e4aeb119e6aedd92bec3f20688fbb99fcd3477d3653"
+
+	networkFileName = "network.json"
+)
+
+// NetworkProfile bundles parameters which should be shared by all nodes of the network
+type NetworkProfile struct {
+	Name          string
+	Network       uint32
+	GenesisConf   *GenesisConf
+	BootNodes     []string
+	IpfsBootNodes []string
+	SwarmKey      string
+	Consensus     *ConsensusConf
+	Validation    *ValidationConfig
+}
+
+var networkProfiles = map[string]func() *NetworkProfile{
+	TestnetProfile: testnetProfile,
+	DevnetProfile:  devnetProfile,
+}
+
+func testnetProfile() *NetworkProfile {
+	return &NetworkProfile{
+		Name:    TestnetProfile,
+		Network: 0x1,
+		GenesisConf: &GenesisConf{
+			FirstCeremonyTime: DefaultCeremonyTime,
+			GodAddress:        common.HexToAddress(DefaultGodAddress),
+		},
+		BootNodes:     DefaultBootstrapNodes,
+		IpfsBootNodes: DefaultIpfsBootstrapNodes,
+		SwarmKey:      DefaultSwarmKey,
+		Consensus:     GetDefaultConsensusConfig(),
+		Validation:    &ValidationConfig{},
+	}
+}
+
+// devnetProfile describes a local network which is started by a single automining node,
+// the god address is the address of that node unless it is set by --godaddress
+func devnetProfile() *NetworkProfile {
+	consensus := GetDefaultConsensusConfig()
+	consensus.Automine = true
+	return &NetworkProfile{
+		Name:    DevnetProfile,
+		Network: 0x2,
+		GenesisConf: &GenesisConf{
+			GodBalance: new(big.Int).Mul(common.DnaBase, big.NewInt(1000000)),
+		},
+		SwarmKey:  DevnetSwarmKey,
+		Consensus: consensus,
+		Validation: &ValidationConfig{
+			ValidationInterval:       time.Hour,
+			FlipLotteryDuration:      time.Minute,
+			ShortSessionDuration:     time.Minute,
+			LongSessionDuration:      2 * time.Minute,
+			AfterLongSessionDuration: 30 * time.Second,
+		},
+	}
+}
+
+// GetNetworkProfile returns bundled parameters of the network, nil is returned for the custom profile
+func GetNetworkProfile(name string) (*NetworkProfile, error) {
+	if name == CustomProfile {
+		return nil, nil
+	}
+	if profile, ok := networkProfiles[name]; ok {
+		return profile(), nil
+	}
+	return nil, errors.Errorf("unknown network profile %v", name)
+}
+
+func applyNetworkProfile(cfg *Config, name string) error {
+	profile, err := GetNetworkProfile(name)
+	if err != nil {
+		return err
+	}
+	cfg.NetworkProfile = name
+	if profile == nil {
+		return nil
+	}
+	var bootNodes []*enode.Node
+	for _, item := range profile.BootNodes {
+		bootNode, err := enode.ParseV4(item)
+		if err != nil {
+			log.Warn("Cant parse bootstrap node", "node", item)
+			continue
+		}
+		bootNodes = append(bootNodes, bootNode)
+	}
+	cfg.Network = profile.Network
+	cfg.GenesisConf = profile.GenesisConf
+	cfg.P2P.BootstrapNodes = bootNodes
+	cfg.IpfsConf.BootNodes = profile.IpfsBootNodes
+	cfg.IpfsConf.SwarmKey = profile.SwarmKey
+	cfg.Consensus = profile.Consensus
+	cfg.Validation = profile.Validation
+	return nil
+}
+
+type datadirNetwork struct {
+	Profile string `json:"profile"`
+	Network uint32 `json:"network"`
+}
+
+// CheckDataDirNetwork binds the datadir to the network profile on the first start
+// and refuses to use it with another profile later. If the datadir already contains a chain,
+// it is bound to the network of the chain, which is recognized by chainNetwork among the given candidates.
+func (c *Config) CheckDataDirNetwork(chainNetwork func(candidates []uint32) (network uint32, stored bool, err error)) error {
+	path := filepath.Join(c.DataDir, networkFileName)
+	current := datadirNetwork{
+		Profile: c.NetworkProfile,
+		Network: c.Network,
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		saved := current
+		network, stored, err := chainNetwork(c.candidateNetworks())
+		if err != nil {
+			return errors.Wrapf(err, "cannot recognize the network of datadir %v", c.DataDir)
+		}
+		if stored {
+			saved = datadirNetwork{
+				Profile: profileOfNetwork(network),
+				Network: network,
+			}
+		}
+		if err := os.MkdirAll(c.DataDir, 0755); err != nil {
+			return err
+		}
+		data, err := json.Marshal(saved)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		return checkDataDirNetwork(c.DataDir, saved, current)
+	}
+	if err != nil {
+		return err
+	}
+	var saved datadirNetwork
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return errors.Errorf("cannot parse %v", path)
+	}
+	return checkDataDirNetwork(c.DataDir, saved, current)
+}
+
+func checkDataDirNetwork(dataDir string, saved, current datadirNetwork) error {
+	if saved != current {
+		return errors.Errorf("datadir %v was created for network %v (id %v), current network is %v (id %v)",
+			dataDir, saved.Profile, saved.Network, current.Profile, current.Network)
+	}
+	return nil
+}
+
+// candidateNetworks returns ids of bundled networks and the configured one
+func (c *Config) candidateNetworks() []uint32 {
+	networks := []uint32{c.Network}
+	for _, profile := range networkProfiles {
+		if network := profile().Network; network != c.Network {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+func profileOfNetwork(network uint32) string {
+	for name, profile := range networkProfiles {
+		if profile().Network == network {
+			return name
+		}
+	}
+	return CustomProfile
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyNetworkProfile(t *testing.T) {
+	cfg := getDefaultConfig(DefaultDataDir)
+	require.NoError(t, applyNetworkProfile(cfg, DevnetProfile))
+	require.Equal(t, uint32(0x2), cfg.Network)
+	require.Equal(t, DevnetSwarmKey, cfg.IpfsConf.SwarmKey)
+	require.Empty(t, cfg.P2P.BootstrapNodes)
+	require.True(t, cfg.Consensus.Automine)
+	require.Equal(t, common.Address{}, cfg.GenesisConf.GodAddress)
+	require.Equal(t, 1, cfg.GenesisConf.GodBalance.Sign())
+
+	cfg = getDefaultConfig(DefaultDataDir)
+	require.NoError(t, applyNetworkProfile(cfg, TestnetProfile))
+	require.Equal(t, uint32(0x1), cfg.Network)
+	require.Len(t, cfg.P2P.BootstrapNodes, len(DefaultBootstrapNodes))
+
+	cfg = getDefaultConfig(DefaultDataDir)
+	require.NoError(t, applyNetworkProfile(cfg, CustomProfile))
+	require.Equal(t, CustomProfile, cfg.NetworkProfile)
+
+	require.Error(t, applyNetworkProfile(cfg, "unknown"))
+}
+
+func TestConfig_CheckDataDirNetwork(t *testing.T) {
+	dir, err := ioutil.TempDir("", "network")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	emptyChain := func(candidates []uint32) (uint32, bool, error) {
+		return 0, false, nil
+	}
+
+	cfg := getDefaultConfig(dir)
+	require.NoError(t, applyNetworkProfile(cfg, DevnetProfile))
+	require.NoError(t, cfg.CheckDataDirNetwork(emptyChain))
+	require.NoError(t, cfg.CheckDataDirNetwork(emptyChain))
+
+	cfg = getDefaultConfig(dir)
+	require.NoError(t, applyNetworkProfile(cfg, TestnetProfile))
+	require.Error(t, cfg.CheckDataDirNetwork(emptyChain))
+}
+
+func TestConfig_CheckDataDirNetwork_ExistingChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "network")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	testnetChain := func(candidates []uint32) (uint32, bool, error) {
+		require.Contains(t, candidates, uint32(0x1))
+		require.Contains(t, candidates, uint32(0x2))
+		return 0x1, true, nil
+	}
+
+	// the datadir is bound to the network of the stored chain, not to the current profile
+	cfg := getDefaultConfig(dir)
+	require.NoError(t, applyNetworkProfile(cfg, DevnetProfile))
+	require.Error(t, cfg.CheckDataDirNetwork(testnetChain))
+
+	cfg = getDefaultConfig(dir)
+	require.NoError(t, applyNetworkProfile(cfg, TestnetProfile))
+	require.NoError(t, cfg.CheckDataDirNetwork(testnetChain))
+
+	unknownChain := func(candidates []uint32) (uint32, bool, error) {
+		return 0, false, errors.New("unknown genesis")
+	}
+	require.NoError(t, os.Remove(filepath.Join(dir, networkFileName)))
+	require.Error(t, cfg.CheckDataDirNetwork(unknownChain))
+}
+
+func TestProfileOfNetwork(t *testing.T) {
+	require.Equal(t, TestnetProfile, profileOfNetwork(0x1))
+	require.Equal(t, DevnetProfile, profileOfNetwork(0x2))
+	require.Equal(t, CustomProfile, profileOfNetwork(0x10))
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type SignerConfig struct {
+	// Socket is the unix socket of the external signer which keeps the node key, the local node key is used if it is empty
+	Socket string
+	// Timeout limits the signer response time including the confirmation of spends
+	Timeout time.Duration
+}
+
+func GetDefaultSignerConfig() *SignerConfig {
+	return &SignerConfig{
+		Timeout: time.Minute,
+	}
+}
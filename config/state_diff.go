@@ -0,0 +1,12 @@
+package config
+
+type StateDiffConfig struct {
+	// Enabled turns on saving account and identity changes of every block
+	Enabled bool
+	// RetentionBlocks is the number of last blocks to keep state diffs for, diffs of all blocks are kept if it is 0
+	RetentionBlocks uint64
+}
+
+func GetDefaultStateDiffConfig() *StateDiffConfig {
+	return &StateDiffConfig{}
+}
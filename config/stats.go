@@ -0,0 +1,12 @@
+package config
+
+type StatsConfig struct {
+	// Enabled turns on saving rewards and validation stats of blocks which finish epochs
+	Enabled bool
+	// RetentionEpochs is the number of last epochs to keep stats for, stats of all epochs are kept if it is 0
+	RetentionEpochs uint16
+}
+
+func GetDefaultStatsConfig() *StatsConfig {
+	return &StatsConfig{}
+}
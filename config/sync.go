@@ -3,4 +3,6 @@ package config
 type SyncConfig struct {
 	FastSync      bool
 	ForceFullSync uint64
+	// SnapshotV2 turns on publishing chunked v2 snapshots besides v1 ones
+	SnapshotV2 bool
 }
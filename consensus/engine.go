@@ -167,7 +167,10 @@ func (engine *Engine) loop() {
 
 		engine.process = "Check if I'm proposer"
 
-		isProposer, proposerHash, proposerProof := engine.chain.GetProposerSortition()
+		isProposer, proposerHash, proposerProof, err := engine.chain.GetProposerSortition()
+		if err != nil {
+			engine.log.Error("Cannot check if the node is proposer", "err", err)
+		}
 
 		var block *types.Block
 		if isProposer {
@@ -276,7 +279,11 @@ func (engine *Engine) completeRound(round uint64) {
 }
 
 func (engine *Engine) proposeBlock(hash common.Hash, proof []byte) *types.Block {
-	block := engine.chain.ProposeBlock()
+	block, err := engine.chain.ProposeBlock()
+	if err != nil {
+		engine.log.Error("Cannot propose block", "err", err)
+		return nil
+	}
 
 	engine.log.Info("Proposed block", "block", block.Hash().Hex(), "txs", len(block.Body.Transactions))
 
@@ -410,7 +417,12 @@ func (engine *Engine) vote(round uint64, step uint16, block common.Hash) {
 		if b, err := engine.proposals.GetBlockByHash(round, block); err == nil {
 			vote.Header.TurnOffline = engine.offlineDetector.VoteForOffline(b)
 		}
-		vote.Signature = engine.secStore.Sign(vote.Header.SignatureHash().Bytes())
+		signature, err := engine.secStore.SignVote(vote.Header)
+		if err != nil {
+			engine.log.Error("Cannot sign vote", "err", err)
+			return
+		}
+		vote.Signature = signature
 		engine.pm.SendVote(&vote)
 
 		engine.log.Info("Voted for", "step", step, "block", block.Hex())
@@ -0,0 +1,258 @@
+package ceremony
+
+import (
+	"encoding/json"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+const (
+	archiveFileName = "epoch.json"
+	flipFileExt     = ".flip"
+)
+
+type ArchivedFlip struct {
+	Cid    string         `json:"cid"`
+	Author common.Address `json:"author"`
+	Extra  bool           `json:"extra,omitempty"`
+	// Answer and WrongWords are given by the node, they are empty for own flips
+	Answer     string `json:"answer,omitempty"`
+	WrongWords bool   `json:"wrongWords,omitempty"`
+	// Status, QualifiedAnswer and QualifiedWrongWords are the final flip qualification by the long session answers
+	Status              string `json:"status"`
+	QualifiedAnswer     string `json:"qualifiedAnswer"`
+	QualifiedWrongWords bool   `json:"qualifiedWrongWords"`
+}
+
+type EpochArchive struct {
+	Epoch        uint16          `json:"epoch"`
+	Address      common.Address  `json:"address"`
+	OwnFlips     []*ArchivedFlip `json:"ownFlips"`
+	ShortFlips   []*ArchivedFlip `json:"shortFlips"`
+	LongFlips    []*ArchivedFlip `json:"longFlips"`
+	ShortAnswers hexutil.Bytes   `json:"shortAnswers"`
+	LongAnswers  hexutil.Bytes   `json:"longAnswers"`
+}
+
+// Archiver keeps own flips and answers of past epochs, every epoch is stored in a separate directory
+type Archiver struct {
+	dir string
+}
+
+func NewArchiver(dir string) *Archiver {
+	return &Archiver{
+		dir: dir,
+	}
+}
+
+// Epochs returns archived epochs in ascending order
+func (a *Archiver) Epochs() ([]uint16, error) {
+	if a.dir == "" {
+		return nil, errors.New("archive directory is not configured")
+	}
+	items, err := ioutil.ReadDir(a.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var epochs []uint16
+	for _, item := range items {
+		if !item.IsDir() {
+			continue
+		}
+		epoch, err := strconv.ParseUint(item.Name(), 10, 16)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(a.dir, item.Name(), archiveFileName)); err != nil {
+			continue
+		}
+		epochs = append(epochs, uint16(epoch))
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+	return epochs, nil
+}
+
+func (a *Archiver) Read(epoch uint16) (*EpochArchive, error) {
+	data, err := ioutil.ReadFile(filepath.Join(a.epochDir(epoch), archiveFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("epoch %v is not archived", epoch)
+		}
+		return nil, err
+	}
+	archive := new(EpochArchive)
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, errors.Wrap(err, "cannot parse archive")
+	}
+	return archive, nil
+}
+
+// ReadFlip returns the decrypted own flip of the epoch
+func (a *Archiver) ReadFlip(epoch uint16, hash string) ([]byte, error) {
+	c, err := cid.Decode(hash)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(a.epochDir(epoch), c.String()+flipFileExt))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("flip is not archived")
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (a *Archiver) write(archive *EpochArchive, flips map[string][]byte) error {
+	dir := a.epochDir(archive.Epoch)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for hash, data := range flips {
+		if err := ioutil.WriteFile(filepath.Join(dir, hash+flipFileExt), data, 0600); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the summary is written last, so partially written epochs are not listed
+	return ioutil.WriteFile(filepath.Join(dir, archiveFileName), data, 0600)
+}
+
+func (a *Archiver) epochDir(epoch uint16) string {
+	return filepath.Join(a.dir, strconv.Itoa(int(epoch)))
+}
+
+// collectArchive gathers own epoch data which is going to be cleared, own flips are returned to be decrypted.
+// Nil is returned if the node did not take part in the ceremony.
+func (vc *ValidationCeremony) collectArchive() (*EpochArchive, [][]byte) {
+	self := vc.secStore.GetAddress()
+	archive := &EpochArchive{
+		Epoch:        vc.epoch,
+		Address:      self,
+		ShortAnswers: vc.epochDb.ReadOwnShortAnswersBits(),
+		LongAnswers:  vc.qualification.longAnswers[self],
+	}
+	if len(vc.candidates) == 0 {
+		if archive.ShortAnswers == nil && archive.LongAnswers == nil {
+			return nil, nil
+		}
+		return archive, nil
+	}
+
+	qualifications := vc.qualification.qualifyFlips(uint(len(vc.flips)), vc.candidates, vc.longFlipsPerCandidate)
+	archivedFlip := func(cid []byte) *ArchivedFlip {
+		item := &ArchivedFlip{
+			Cid:    cidString(cid),
+			Author: vc.flipAuthorMap[rlp.Hash(cid)],
+		}
+		if idx := flipPos(vc.flips, cid); idx >= 0 {
+			item.Status = FlipStatusName(qualifications[idx].status)
+			item.QualifiedAnswer = answerName(qualifications[idx].answer)
+			item.QualifiedWrongWords = qualifications[idx].wrongWords
+		}
+		return item
+	}
+
+	var ownFlips [][]byte
+	for _, flip := range vc.flips {
+		if vc.flipAuthorMap[rlp.Hash(flip)] == self {
+			ownFlips = append(ownFlips, flip)
+			archive.OwnFlips = append(archive.OwnFlips, archivedFlip(flip))
+		}
+	}
+
+	shortAnswers := types.NewAnswersFromBits(uint(len(vc.shortFlipsToSolve)), archive.ShortAnswers)
+	for i, flip := range vc.shortFlipsToSolve {
+		item := archivedFlip(flip)
+		item.Extra = i >= int(common.ShortSessionFlipsCount())
+		item.Answer, item.WrongWords = ownAnswer(shortAnswers, i, archive.ShortAnswers != nil)
+		archive.ShortFlips = append(archive.ShortFlips, item)
+	}
+	longAnswers := types.NewAnswersFromBits(uint(len(vc.longFlipsToSolve)), archive.LongAnswers)
+	for i, flip := range vc.longFlipsToSolve {
+		item := archivedFlip(flip)
+		item.Answer, item.WrongWords = ownAnswer(longAnswers, i, archive.LongAnswers != nil)
+		archive.LongFlips = append(archive.LongFlips, item)
+	}
+	if len(ownFlips) == 0 && len(archive.ShortFlips) == 0 && len(archive.LongFlips) == 0 {
+		return nil, nil
+	}
+	return archive, ownFlips
+}
+
+func (vc *ValidationCeremony) archiveEpoch(archive *EpochArchive, ownFlips [][]byte) {
+	flips := make(map[string][]byte)
+	for _, flip := range ownFlips {
+		data, err := vc.flipper.DecryptOwnFlip(flip, archive.Epoch)
+		if err != nil {
+			vc.log.Warn("Cannot archive own flip", "cid", cidString(flip), "err", err)
+			continue
+		}
+		flips[cidString(flip)] = data
+	}
+	if err := vc.archiver.write(archive, flips); err != nil {
+		vc.log.Error("Cannot archive epoch", "epoch", archive.Epoch, "err", err)
+		return
+	}
+	vc.log.Info("Epoch archived", "epoch", archive.Epoch, "flips", len(flips))
+}
+
+func ownAnswer(answers *types.Answers, idx int, sent bool) (string, bool) {
+	if !sent {
+		return "", false
+	}
+	answer, wrongWords := answers.Answer(uint(idx))
+	return answerName(answer), wrongWords
+}
+
+func cidString(data []byte) string {
+	c, err := cid.Cast(data)
+	if err != nil {
+		return hexutil.Encode(data)
+	}
+	return c.String()
+}
+
+func answerName(answer types.Answer) string {
+	switch answer {
+	case types.Left:
+		return "left"
+	case types.Right:
+		return "right"
+	case types.Inappropriate:
+		return "inappropriate"
+	default:
+		return "none"
+	}
+}
+
+// FlipStatusName returns the name of the flip status used by the RPC and archives
+func FlipStatusName(status FlipStatus) string {
+	switch status {
+	case Qualified:
+		return "qualified"
+	case WeaklyQualified:
+		return "weaklyQualified"
+	case QualifiedByNone:
+		return "qualifiedByNone"
+	default:
+		return "notQualified"
+	}
+}
@@ -0,0 +1,171 @@
+package ceremony
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/idena-network/idena-go/secstore"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tm-db"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestArchiver(t *testing.T) {
+	r := require.New(t)
+	dir, err := ioutil.TempDir("", "archive")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	archiver := NewArchiver(dir)
+	epochs, err := archiver.Epochs()
+	r.NoError(err)
+	r.Empty(epochs)
+
+	hash, _ := multihash.Sum([]byte("flip"), multihash.SHA2_256, -1)
+	flipCid := cid.NewCidV1(cid.Raw, hash).String()
+
+	for _, epoch := range []uint16{12, 3} {
+		archive := &EpochArchive{
+			Epoch:   epoch,
+			Address: common.Address{0x1},
+			OwnFlips: []*ArchivedFlip{
+				{Cid: flipCid, Status: FlipStatusName(Qualified), QualifiedAnswer: answerName(1)},
+			},
+			ShortAnswers: []byte{0x1},
+		}
+		r.NoError(archiver.write(archive, map[string][]byte{flipCid: {0x1, 0x2}}))
+	}
+	r.NoError(os.Mkdir(dir+"/not-epoch", 0700))
+
+	epochs, err = archiver.Epochs()
+	r.NoError(err)
+	r.Equal([]uint16{3, 12}, epochs)
+
+	archive, err := archiver.Read(12)
+	r.NoError(err)
+	r.Equal(uint16(12), archive.Epoch)
+	r.Len(archive.OwnFlips, 1)
+	r.Equal("qualified", archive.OwnFlips[0].Status)
+	r.Equal("left", archive.OwnFlips[0].QualifiedAnswer)
+
+	_, err = archiver.Read(5)
+	r.Error(err)
+
+	data, err := archiver.ReadFlip(3, flipCid)
+	r.NoError(err)
+	r.Equal([]byte{0x1, 0x2}, data)
+
+	_, err = archiver.ReadFlip(3, "../epoch.json")
+	r.Error(err)
+}
+
+func TestValidationCeremony_collectArchive(t *testing.T) {
+	r := require.New(t)
+
+	key, _ := crypto.GenerateKey()
+	secStore := secstore.NewSecStore()
+	secStore.AddKey(crypto.FromECDSA(key))
+	self := secStore.GetAddress()
+
+	epochDb := database.NewEpochDb(db.NewMemDB(), 3)
+	vc := &ValidationCeremony{
+		secStore:      secStore,
+		epoch:         3,
+		epochDb:       epochDb,
+		qualification: NewQualification(epochDb),
+		flipAuthorMap: make(map[common.Hash]common.Address),
+	}
+
+	// the first flip is own, the rest are made by other authors
+	var cids []string
+	for i := 0; i < 7; i++ {
+		hash, _ := multihash.Sum([]byte{byte(i)}, multihash.SHA2_256, -1)
+		flip := cid.NewCidV1(cid.Raw, hash)
+		vc.flips = append(vc.flips, flip.Bytes())
+		cids = append(cids, flip.String())
+		author := common.Address{byte(i + 1)}
+		if i == 0 {
+			author = self
+		}
+		vc.flipAuthorMap[rlp.Hash(flip.Bytes())] = author
+	}
+
+	vc.candidates = []*candidate{{Address: self}}
+	vc.longFlipsPerCandidate = [][]int{{1, 2}}
+	for i := 0; i < 4; i++ {
+		vc.candidates = append(vc.candidates, &candidate{Address: common.Address{0xa, byte(i)}})
+		vc.longFlipsPerCandidate = append(vc.longFlipsPerCandidate, []int{0, 1, 2})
+	}
+
+	// the first flip is qualified as right, the second one as left with wrong words, the third one is not qualified
+	for i, c := range vc.candidates[1:] {
+		answers := types.NewAnswers(3)
+		answers.Right(0)
+		answers.Left(1)
+		answers.WrongWords(1)
+		if i < 2 {
+			answers.Left(2)
+		} else {
+			answers.Right(2)
+		}
+		vc.qualification.longAnswers[c.Address] = answers.Bytes()
+	}
+	longAnswers := types.NewAnswers(2)
+	longAnswers.Left(0)
+	longAnswers.Right(1)
+	vc.qualification.longAnswers[self] = longAnswers.Bytes()
+	vc.longFlipsToSolve = [][]byte{vc.flips[1], vc.flips[2]}
+
+	vc.shortFlipsToSolve = vc.flips[1:]
+	shortAnswers := types.NewAnswers(uint(len(vc.shortFlipsToSolve)))
+	shortAnswers.Left(0)
+	shortAnswers.Right(1)
+	shortAnswers.WrongWords(1)
+	shortAnswers.Inappropriate(5)
+	epochDb.WriteOwnShortAnswers(shortAnswers)
+
+	archive, ownFlips := vc.collectArchive()
+	r.NotNil(archive)
+	r.Equal(uint16(3), archive.Epoch)
+	r.Equal(self, archive.Address)
+
+	r.Equal([][]byte{vc.flips[0]}, ownFlips)
+	r.Len(archive.OwnFlips, 1)
+	r.Equal(cids[0], archive.OwnFlips[0].Cid)
+	r.Equal(self, archive.OwnFlips[0].Author)
+	r.Empty(archive.OwnFlips[0].Answer)
+	r.Equal("qualified", archive.OwnFlips[0].Status)
+	r.Equal("right", archive.OwnFlips[0].QualifiedAnswer)
+
+	r.Len(archive.ShortFlips, 6)
+	for i, item := range archive.ShortFlips {
+		r.Equal(cids[i+1], item.Cid)
+		r.Equal(common.Address{byte(i + 2)}, item.Author)
+		r.Equal(i == 5, item.Extra)
+	}
+	r.Equal("left", archive.ShortFlips[0].Answer)
+	r.False(archive.ShortFlips[0].WrongWords)
+	r.Equal("right", archive.ShortFlips[1].Answer)
+	r.True(archive.ShortFlips[1].WrongWords)
+	r.Equal("none", archive.ShortFlips[2].Answer)
+	r.Equal("inappropriate", archive.ShortFlips[5].Answer)
+	r.Equal("notQualified", archive.ShortFlips[2].Status)
+
+	r.Len(archive.LongFlips, 2)
+	r.Equal(cids[1], archive.LongFlips[0].Cid)
+	r.Equal("left", archive.LongFlips[0].Answer)
+	r.Equal("qualified", archive.LongFlips[0].Status)
+	r.Equal("left", archive.LongFlips[0].QualifiedAnswer)
+	r.True(archive.LongFlips[0].QualifiedWrongWords)
+	r.Equal(cids[2], archive.LongFlips[1].Cid)
+	r.Equal("right", archive.LongFlips[1].Answer)
+	r.Equal("notQualified", archive.LongFlips[1].Status)
+	r.Equal("none", archive.LongFlips[1].QualifiedAnswer)
+	r.False(archive.LongFlips[1].Extra)
+}
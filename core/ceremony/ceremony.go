@@ -34,6 +34,8 @@ import (
 
 const (
 	LotterySeedLag = 100
+
+	flipsReadinessCheckDelay = time.Second
 )
 
 const (
@@ -80,12 +82,17 @@ type ValidationCeremony struct {
 	epochApplyingCache       map[uint64]epochApplyingCache
 	validationStartCtxCancel context.CancelFunc
 	validationStartMutex     sync.Mutex
+	archiver                 *Archiver
+	flipsReady               bool
+	flipsReadyMutex          sync.Mutex
+	flipsReadinessCheck      chan struct{}
 }
 
 type epochApplyingCache struct {
 	epochApplyingResult map[common.Address]cacheValue
 	validationFailed    bool
 	validationAuthors   *types.ValidationAuthors
+	validationResults   []*types.IdentityValidationResult
 }
 
 type cacheValue struct {
@@ -98,7 +105,7 @@ type cacheValue struct {
 type blockHandler func(block *types.Block)
 
 func NewValidationCeremony(appState *appstate.AppState, bus eventbus.Bus, flipper *flip.Flipper, secStore *secstore.SecStore, db dbm.DB, mempool *mempool.TxPool,
-	chain *blockchain.Blockchain, syncer protocol.Syncer, keysPool *mempool.KeysPool, config *config.Config) *ValidationCeremony {
+	chain *blockchain.Blockchain, syncer protocol.Syncer, keysPool *mempool.KeysPool, config *config.Config, archiver *Archiver) *ValidationCeremony {
 
 	vc := &ValidationCeremony{
 		flipper:            flipper,
@@ -113,6 +120,9 @@ func NewValidationCeremony(appState *appstate.AppState, bus eventbus.Bus, flippe
 		chain:              chain,
 		syncer:             syncer,
 		config:             config,
+		archiver:           archiver,
+		// the buffer of one coalesces check requests which arrive while the check is in progress
+		flipsReadinessCheck: make(chan struct{}, 1),
 	}
 
 	vc.blockHandlers = map[state.ValidationPeriod]blockHandler{
@@ -136,6 +146,15 @@ func (vc *ValidationCeremony) Initialize(currentBlock *types.Block) {
 			vc.addBlock(newBlockEvent.Block)
 		})
 
+	_ = vc.bus.Subscribe(events.NewFlipKeyID, func(e eventbus.Event) {
+		// the event is published under the keys pool lock, so flips are decrypted asynchronously
+		select {
+		case vc.flipsReadinessCheck <- struct{}{}:
+		default:
+		}
+	})
+	go vc.flipsReadinessLoop()
+
 	_ = vc.bus.Subscribe(events.FastSyncCompleted, func(event eventbus.Event) {
 		vc.completeEpoch()
 		vc.restoreState()
@@ -151,9 +170,16 @@ func (vc *ValidationCeremony) addBlock(block *types.Block) {
 
 	// completeEpoch if finished
 	if block.Header.Flags().HasFlag(types.ValidationFinished) {
+		if applyingCache, ok := vc.epochApplyingCache[block.Height()]; ok && len(applyingCache.validationResults) > 0 {
+			database.NewRepo(vc.db).WriteValidationResults(applyingCache.validationResults)
+		}
 		vc.completeEpoch()
 		vc.startValidationShortSessionTimer()
 		vc.generateFlipKeyWordPairs(vc.appState.State.FlipWordsSeed().Bytes())
+		vc.publishProgress(&events.CeremonyProgressEvent{
+			Stage: events.CeremonyEpochApplied,
+			Count: vc.appState.ValidatorsCache.NetworkSize(),
+		})
 	}
 }
 
@@ -180,9 +206,12 @@ func (vc *ValidationCeremony) GetLongFlipsToSolve() [][]byte {
 }
 
 func (vc *ValidationCeremony) SubmitShortAnswers(answers *types.Answers) (common.Hash, error) {
+	salt, err := getShortAnswersSalt(vc.epoch, vc.secStore)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	vc.mutex.Lock()
 	prevAnswers := vc.epochDb.ReadOwnShortAnswersBits()
-	salt := getShortAnswersSalt(vc.epoch, vc.secStore)
 	var hash [32]byte
 	if len(prevAnswers) == 0 {
 		vc.epochDb.WriteOwnShortAnswers(answers)
@@ -204,14 +233,7 @@ func (vc *ValidationCeremony) ShortSessionFlipsCount() uint {
 }
 
 func (vc *ValidationCeremony) LongSessionFlipsCount() uint {
-	if len(vc.candidates) == 0 {
-		return 1
-	}
-	count := uint(len(vc.flips) * common.LongSessionTesters / len(vc.candidates))
-	if count == 0 {
-		count = 1
-	}
-	return count
+	return longSessionFlipsCount(len(vc.flips), len(vc.candidates))
 }
 
 func (vc *ValidationCeremony) restoreState() {
@@ -254,7 +276,15 @@ func (vc *ValidationCeremony) startValidationShortSessionTimer() {
 func (vc *ValidationCeremony) completeEpoch() {
 	if vc.epoch != vc.appState.State.Epoch() {
 		edb := vc.epochDb
+		var archive *EpochArchive
+		var ownFlips [][]byte
+		if vc.config.Archive != nil && vc.config.Archive.Enabled && vc.archiver != nil {
+			archive, ownFlips = vc.collectArchive()
+		}
 		go func() {
+			if archive != nil {
+				vc.archiveEpoch(archive, ownFlips)
+			}
 			vc.dropFlips(edb)
 			edb.Clear()
 		}()
@@ -276,6 +306,9 @@ func (vc *ValidationCeremony) completeEpoch() {
 	vc.longFlipsPerCandidate = nil
 	vc.shortFlipsToSolve = nil
 	vc.longFlipsToSolve = nil
+	vc.flipsReadyMutex.Lock()
+	vc.flipsReady = false
+	vc.flipsReadyMutex.Unlock()
 	vc.keySent = false
 	vc.shortAnswersSent = false
 	vc.evidenceSent = false
@@ -356,32 +389,21 @@ func (vc *ValidationCeremony) calculateCeremonyCandidates() {
 		return
 	}
 
-	vc.flipAuthorMapLock.Lock()
-	vc.candidates, vc.nonCandidates, vc.flips, vc.flipsPerAuthor, vc.flipAuthorMap = vc.getCandidatesAndFlips()
-	vc.flipAuthorMapLock.Unlock()
-
-	shortFlipsPerCandidate := SortFlips(vc.flipsPerAuthor, vc.candidates, vc.flips, int(vc.ShortSessionFlipsCount()+common.ShortSessionExtraFlipsCount()), seed, false, nil)
-
-	chosenFlips := make(map[int]bool)
-	for _, a := range shortFlipsPerCandidate {
-		for _, f := range a {
-			chosenFlips[f] = true
-		}
-	}
-
-	longFlipsPerCandidate := SortFlips(vc.flipsPerAuthor, vc.candidates, vc.flips, int(vc.LongSessionFlipsCount()), common.ReverseBytes(seed), true, chosenFlips)
-
-	vc.shortFlipsPerCandidate = shortFlipsPerCandidate
-	vc.longFlipsPerCandidate = longFlipsPerCandidate
+	vc.distributeFlips(seed)
 
 	vc.shortFlipsToSolve = getFlipsToSolve(vc.secStore.GetAddress(), vc.candidates, vc.shortFlipsPerCandidate, vc.flips)
 	vc.longFlipsToSolve = getFlipsToSolve(vc.secStore.GetAddress(), vc.candidates, vc.longFlipsPerCandidate, vc.flips)
 
 	if vc.shouldInteractWithNetwork() {
-		go vc.flipper.Load(vc.shortFlipsToSolve)
-		go vc.flipper.Load(vc.longFlipsToSolve)
+		go vc.loadFlips(vc.shortFlipsToSolve, events.CeremonyShortFlipsLoaded)
+		go vc.loadFlips(vc.longFlipsToSolve, events.CeremonyLongFlipsLoaded)
 	}
 
+	vc.publishProgress(&events.CeremonyProgressEvent{
+		Stage: events.CeremonyCandidatesCalculated,
+		Count: len(vc.candidates),
+	})
+
 	vc.logInfoWithInteraction("Ceremony candidates", "cnt", len(vc.candidates))
 
 	if len(vc.candidates) < 100 {
@@ -396,6 +418,18 @@ func (vc *ValidationCeremony) calculateCeremonyCandidates() {
 	vc.logInfoWithInteraction("Should solve flips in long session", "cnt", len(vc.longFlipsToSolve))
 }
 
+// distributeFlips reads ceremony candidates and their flips from the state and assigns flips to solve using the lottery seed
+func (vc *ValidationCeremony) distributeFlips(seed []byte) {
+	vc.flipAuthorMapLock.Lock()
+	vc.candidates, vc.nonCandidates, vc.flips, vc.flipsPerAuthor, vc.flipAuthorMap = vc.getCandidatesAndFlips()
+	vc.flipAuthorMapLock.Unlock()
+
+	shortFlipsPerCandidate, longFlipsPerCandidate := assignFlips(vc.flipsPerAuthor, vc.candidates, vc.flips, seed)
+
+	vc.shortFlipsPerCandidate = shortFlipsPerCandidate
+	vc.longFlipsPerCandidate = longFlipsPerCandidate
+}
+
 func (vc *ValidationCeremony) shouldInteractWithNetwork() bool {
 
 	if !vc.syncer.IsSyncing() {
@@ -436,6 +470,60 @@ func (vc *ValidationCeremony) broadcastFlipKey(appState *appstate.AppState) {
 
 	vc.keysPool.Add(signedMsg, true)
 	vc.keySent = true
+	vc.publishProgress(&events.CeremonyProgressEvent{
+		Stage: events.CeremonyFlipKeyBroadcast,
+	})
+}
+
+func (vc *ValidationCeremony) loadFlips(cids [][]byte, stage string) {
+	if vc.flipper.Load(cids) {
+		vc.publishProgress(&events.CeremonyProgressEvent{
+			Stage: stage,
+			Count: len(cids),
+		})
+		vc.checkFlipsReadiness()
+	}
+}
+
+// flipsReadinessLoop checks flips once per burst of received keys instead of once per key
+func (vc *ValidationCeremony) flipsReadinessLoop() {
+	for range vc.flipsReadinessCheck {
+		time.Sleep(flipsReadinessCheckDelay)
+		vc.checkFlipsReadiness()
+	}
+}
+
+// checkFlipsReadiness publishes the event once all flips to solve are downloaded and decrypted
+func (vc *ValidationCeremony) checkFlipsReadiness() {
+	vc.flipsReadyMutex.Lock()
+	defer vc.flipsReadyMutex.Unlock()
+	if vc.flipsReady {
+		return
+	}
+	shortFlips, longFlips := vc.GetShortFlipsToSolve(), vc.GetLongFlipsToSolve()
+	if len(shortFlips) == 0 && len(longFlips) == 0 {
+		return
+	}
+	for _, flips := range [][][]byte{shortFlips, longFlips} {
+		for _, cid := range flips {
+			if !vc.flipper.IsFlipReady(cid) {
+				return
+			}
+		}
+	}
+	vc.flipsReady = true
+	vc.publishProgress(&events.CeremonyProgressEvent{
+		Stage: events.CeremonyFlipsReady,
+		Count: len(shortFlips) + len(longFlips),
+	})
+}
+
+func (vc *ValidationCeremony) publishProgress(e *events.CeremonyProgressEvent) {
+	if !vc.shouldInteractWithNetwork() {
+		return
+	}
+	e.Epoch = vc.epoch
+	vc.bus.Publish(e)
 }
 
 func (vc *ValidationCeremony) getCandidatesAndFlips() ([]*candidate, []common.Address, [][]byte, map[int][][]byte, map[common.Hash]common.Address) {
@@ -544,7 +632,15 @@ func (vc *ValidationCeremony) broadcastShortAnswersTx() {
 	}
 
 	key := vc.flipper.GetFlipEncryptionKey()
-	salt := getShortAnswersSalt(vc.epoch, vc.secStore)
+	if key == nil {
+		vc.log.Error("flip key is missing")
+		return
+	}
+	salt, err := getShortAnswersSalt(vc.epoch, vc.secStore)
+	if err != nil {
+		vc.log.Error("Cannot generate short answers salt", "err", err)
+		return
+	}
 
 	if _, err := vc.sendTx(types.SubmitShortAnswersTx, attachments.CreateShortAnswerAttachment(answers, vc.flipKeyWordProof, salt, key)); err == nil {
 		vc.shortAnswersSent = true
@@ -592,20 +688,47 @@ func (vc *ValidationCeremony) broadcastEvidenceMap(block *types.Block) {
 	}
 }
 
+var ceremonyTxStages = map[uint16]string{
+	types.SubmitAnswersHashTx:  events.CeremonyShortAnswersHashSent,
+	types.SubmitShortAnswersTx: events.CeremonyShortAnswersRevealed,
+	types.SubmitLongAnswersTx:  events.CeremonyLongAnswersSent,
+	types.EvidenceTx:           events.CeremonyEvidenceMapSent,
+}
+
 func (vc *ValidationCeremony) sendTx(txType uint16, payload []byte) (common.Hash, error) {
+	hash, err := vc.signAndSendTx(txType, payload)
+	e := &events.CeremonyProgressEvent{
+		Stage:  ceremonyTxStages[txType],
+		TxType: txType,
+		TxHash: hash,
+	}
+	if err != nil {
+		e.Stage = events.CeremonyError
+		e.Error = err.Error()
+	}
+	vc.publishProgress(e)
+	return hash, err
+}
+
+func (vc *ValidationCeremony) signAndSendTx(txType uint16, payload []byte) (common.Hash, error) {
 	vc.mutex.Lock()
 	defer vc.mutex.Unlock()
 
 	signedTx := &types.Transaction{}
+	reserved := false
+	addr := vc.secStore.GetAddress()
 
 	if existTx := vc.epochDb.ReadOwnTx(txType); existTx != nil {
 		rlp.DecodeBytes(existTx, signedTx)
 	} else {
-		addr := vc.secStore.GetAddress()
-		tx := blockchain.BuildTx(vc.appState, addr, nil, txType, decimal.Zero, decimal.Zero, decimal.Zero, 0, 0, payload)
+		epoch := vc.appState.State.Epoch()
+		nonce := vc.mempool.ReserveNonce(addr, epoch)
+		reserved = true
+		tx := blockchain.BuildTx(vc.appState, addr, nil, txType, decimal.Zero, decimal.Zero, decimal.Zero, nonce, epoch, payload)
 		var err error
 		signedTx, err = vc.secStore.SignTx(tx)
 		if err != nil {
+			vc.mempool.ReleaseNonce(addr, epoch, nonce)
 			vc.log.Error(err.Error())
 			return common.Hash{}, err
 		}
@@ -614,6 +737,13 @@ func (vc *ValidationCeremony) sendTx(txType uint16, payload []byte) (common.Hash
 	}
 
 	err := vc.mempool.Add(signedTx)
+	if reserved {
+		if err != nil {
+			vc.mempool.ReleaseNonce(addr, signedTx.Epoch, signedTx.AccountNonce)
+		} else {
+			vc.mempool.ConfirmNonce(addr, signedTx.Epoch, signedTx.AccountNonce)
+		}
+	}
 
 	if err != nil {
 		if !vc.epochDb.HasSuccessfulOwnTx(signedTx.Hash()) {
@@ -689,64 +819,41 @@ func (vc *ValidationCeremony) ApplyNewEpoch(height uint64, appState *appstate.Ap
 
 	intermediateIdentitiesCount := 0
 	epochApplyingValues := make(map[common.Address]cacheValue)
+	var validationResults []*types.IdentityValidationResult
 
 	for idx, candidate := range vc.candidates {
 		addr := candidate.Address
-		var shortScore, longScore, totalScore float32
-		shortFlipsToSolve := vc.shortFlipsPerCandidate[idx]
-		shortFlipPoint, shortQualifiedFlipsCount, shortFlipAnswers, noQualShort := vc.qualification.qualifyCandidate(addr, flipQualificationMap, shortFlipsToSolve, true, notApprovedFlips)
-		addFlipAnswersToStats(shortFlipAnswers, true, stats)
-
-		longFlipsToSolve := vc.longFlipsPerCandidate[idx]
-		longFlipPoint, longQualifiedFlipsCount, longFlipAnswers, noQualLong := vc.qualification.qualifyCandidate(addr, flipQualificationMap, longFlipsToSolve, false, notApprovedFlips)
-		addFlipAnswersToStats(longFlipAnswers, false, stats)
-
-		totalFlipPoints := appState.State.GetShortFlipPoints(addr)
-		totalQualifiedFlipsCount := appState.State.GetQualifiedFlipsCount(addr)
-		approved := approvedCandidatesSet.Contains(addr)
-		missed := !approved
-		fullQual := !noQualShort && !noQualLong
-
-		if shortQualifiedFlipsCount > 0 {
-			shortScore = shortFlipPoint / float32(shortQualifiedFlipsCount)
-		} else if fullQual {
-			missed = true
-		}
-		if longQualifiedFlipsCount > 0 {
-			longScore = longFlipPoint / float32(longQualifiedFlipsCount)
-		} else if fullQual {
-			missed = true
-		}
-		newTotalQualifiedFlipsCount := shortQualifiedFlipsCount + totalQualifiedFlipsCount
-		if newTotalQualifiedFlipsCount > 0 {
-			totalScore = (shortFlipPoint + totalFlipPoints) / float32(newTotalQualifiedFlipsCount)
-		}
+		scores := vc.calculateCandidateScores(appState, idx, flipQualificationMap, approvedCandidatesSet, notApprovedFlips)
+		addFlipAnswersToStats(scores.shortFlipAnswers, true, stats)
+		addFlipAnswersToStats(scores.longFlipAnswers, false, stats)
 
 		identity := appState.State.GetIdentity(addr)
-		newIdentityState := determineNewIdentityState(identity, shortScore, longScore, totalScore,
-			newTotalQualifiedFlipsCount, missed, noQualShort, noQualLong)
+		newIdentityState, reason := determineNewIdentityStateWithReason(identity, scores.shortScore, scores.longScore, scores.totalScore,
+			scores.totalQualifiedFlipsCount, scores.missed, scores.noQualShort, scores.noQualLong)
 		identityBirthday := determineIdentityBirthday(vc.epoch, identity, newIdentityState)
 
 		incSuccessfulInvites(validationAuthors, god, identity, newIdentityState)
 
+		validationResults = append(validationResults, vc.candidateValidationResult(addr, identity.State, newIdentityState, reason, scores))
+
 		value := cacheValue{
 			state:                    newIdentityState,
-			shortQualifiedFlipsCount: shortQualifiedFlipsCount,
-			shortFlipPoint:           shortFlipPoint,
+			shortQualifiedFlipsCount: scores.shortQualifiedFlipsCount,
+			shortFlipPoint:           scores.shortFlipPoint,
 			birthday:                 identityBirthday,
 		}
 
 		epochApplyingValues[addr] = value
 
 		stats.IdentitiesPerAddr[addr] = &statsTypes.IdentityStats{
-			ShortPoint:        shortFlipPoint,
-			ShortFlips:        shortQualifiedFlipsCount,
-			LongPoint:         longFlipPoint,
-			LongFlips:         longQualifiedFlipsCount,
-			Approved:          approved,
-			Missed:            missed,
-			ShortFlipsToSolve: shortFlipsToSolve,
-			LongFlipsToSolve:  longFlipsToSolve,
+			ShortPoint:        scores.shortFlipPoint,
+			ShortFlips:        scores.shortQualifiedFlipsCount,
+			LongPoint:         scores.longFlipPoint,
+			LongFlips:         scores.longQualifiedFlipsCount,
+			Approved:          scores.approved,
+			Missed:            scores.missed,
+			ShortFlipsToSolve: vc.shortFlipsPerCandidate[idx],
+			LongFlipsToSolve:  vc.longFlipsPerCandidate[idx],
 		}
 
 		if value.state == state.Verified || value.state == state.Newbie {
@@ -757,10 +864,15 @@ func (vc *ValidationCeremony) ApplyNewEpoch(height uint64, appState *appstate.Ap
 	if intermediateIdentitiesCount == 0 {
 		vc.log.Warn("validation failed, nobody is validated, identities remains the same")
 		stats.Failed = true
+		for _, result := range validationResults {
+			result.Failed = true
+			result.NewState = result.PrevState
+		}
 		vc.epochApplyingCache[height] = epochApplyingCache{
 			epochApplyingResult: epochApplyingValues,
 			validationAuthors:   validationAuthors,
 			validationFailed:    true,
+			validationResults:   validationResults,
 		}
 		return vc.appState.ValidatorsCache.NetworkSize(), validationAuthors, true
 	}
@@ -771,9 +883,20 @@ func (vc *ValidationCeremony) ApplyNewEpoch(height uint64, appState *appstate.Ap
 
 	for _, addr := range vc.nonCandidates {
 		identity := appState.State.GetIdentity(addr)
-		newIdentityState := determineNewIdentityState(identity, 0, 0, 0, 0, true, false, false)
+		newIdentityState, reason := determineNewIdentityStateWithReason(identity, 0, 0, 0, 0, true, false, false)
 		identityBirthday := determineIdentityBirthday(vc.epoch, identity, newIdentityState)
 
+		if identity.State != state.Undefined && identity.State != state.Killed {
+			validationResults = append(validationResults, &types.IdentityValidationResult{
+				Address:   addr,
+				Epoch:     vc.epoch,
+				PrevState: uint8(identity.State),
+				NewState:  uint8(newIdentityState),
+				Missed:    true,
+				Reason:    reason,
+			})
+		}
+
 		value := cacheValue{
 			state:                    newIdentityState,
 			shortQualifiedFlipsCount: 0,
@@ -788,11 +911,108 @@ func (vc *ValidationCeremony) ApplyNewEpoch(height uint64, appState *appstate.Ap
 		epochApplyingResult: epochApplyingValues,
 		validationAuthors:   validationAuthors,
 		validationFailed:    false,
+		validationResults:   validationResults,
 	}
 
 	return identitiesCount, validationAuthors, false
 }
 
+type candidateScores struct {
+	shortFlipPoint           float32
+	shortQualifiedFlipsCount uint32
+	shortFlipAnswers         map[int]statsTypes.FlipAnswerStats
+	longFlipPoint            float32
+	longQualifiedFlipsCount  uint32
+	longFlipAnswers          map[int]statsTypes.FlipAnswerStats
+	shortScore               float32
+	longScore                float32
+	totalScore               float32
+	totalFlipPoint           float32
+	totalQualifiedFlipsCount uint32
+	approved                 bool
+	missed                   bool
+	noQualShort              bool
+	noQualLong               bool
+	shortAnswersError        string
+	longAnswersError         string
+	shortDisqualified        []disqualifiedFlip
+	longDisqualified         []disqualifiedFlip
+}
+
+func (vc *ValidationCeremony) candidateValidationResult(addr common.Address, prevState, newState state.IdentityState,
+	reason string, scores *candidateScores) *types.IdentityValidationResult {
+	result := &types.IdentityValidationResult{
+		Address:           addr,
+		Epoch:             vc.epoch,
+		PrevState:         uint8(prevState),
+		NewState:          uint8(newState),
+		Candidate:         true,
+		Approved:          scores.approved,
+		Missed:            scores.missed,
+		NoQualShort:       scores.noQualShort,
+		NoQualLong:        scores.noQualLong,
+		ShortPoints:       uint32(scores.shortFlipPoint * 2),
+		ShortFlips:        scores.shortQualifiedFlipsCount,
+		LongPoints:        uint32(scores.longFlipPoint * 2),
+		LongFlips:         scores.longQualifiedFlipsCount,
+		TotalPoints:       uint32(scores.totalFlipPoint * 2),
+		TotalFlips:        scores.totalQualifiedFlipsCount,
+		ShortAnswersError: scores.shortAnswersError,
+		LongAnswersError:  scores.longAnswersError,
+		Reason:            reason,
+	}
+	addDisqualified := func(list []disqualifiedFlip, short bool) {
+		for _, item := range list {
+			result.DisqualifiedFlips = append(result.DisqualifiedFlips, &types.DisqualifiedFlip{
+				Cid:    vc.flips[item.flipIdx],
+				Short:  short,
+				Status: byte(item.status),
+				Reason: item.reason,
+			})
+		}
+	}
+	addDisqualified(scores.shortDisqualified, true)
+	addDisqualified(scores.longDisqualified, false)
+	return result
+}
+
+func (vc *ValidationCeremony) calculateCandidateScores(appState *appstate.AppState, idx int, flipQualificationMap map[int]FlipQualification,
+	approvedCandidates mapset.Set, notApprovedFlips mapset.Set) *candidateScores {
+	addr := vc.candidates[idx].Address
+	scores := &candidateScores{}
+
+	short := vc.qualification.qualifyCandidateFlips(addr, flipQualificationMap, vc.shortFlipsPerCandidate[idx], true, notApprovedFlips)
+	scores.shortFlipPoint, scores.shortQualifiedFlipsCount, scores.shortFlipAnswers, scores.noQualShort = short.point, short.qualifiedFlipsCount, short.flipAnswers, short.noQual
+	scores.shortAnswersError, scores.shortDisqualified = short.answersError, short.disqualified
+
+	long := vc.qualification.qualifyCandidateFlips(addr, flipQualificationMap, vc.longFlipsPerCandidate[idx], false, notApprovedFlips)
+	scores.longFlipPoint, scores.longQualifiedFlipsCount, scores.longFlipAnswers, scores.noQualLong = long.point, long.qualifiedFlipsCount, long.flipAnswers, long.noQual
+	scores.longAnswersError, scores.longDisqualified = long.answersError, long.disqualified
+
+	totalFlipPoints := appState.State.GetShortFlipPoints(addr)
+	totalQualifiedFlipsCount := appState.State.GetQualifiedFlipsCount(addr)
+	scores.approved = approvedCandidates.Contains(addr)
+	scores.missed = !scores.approved
+	fullQual := !scores.noQualShort && !scores.noQualLong
+
+	if scores.shortQualifiedFlipsCount > 0 {
+		scores.shortScore = scores.shortFlipPoint / float32(scores.shortQualifiedFlipsCount)
+	} else if fullQual {
+		scores.missed = true
+	}
+	if scores.longQualifiedFlipsCount > 0 {
+		scores.longScore = scores.longFlipPoint / float32(scores.longQualifiedFlipsCount)
+	} else if fullQual {
+		scores.missed = true
+	}
+	scores.totalQualifiedFlipsCount = scores.shortQualifiedFlipsCount + totalQualifiedFlipsCount
+	scores.totalFlipPoint = scores.shortFlipPoint + totalFlipPoints
+	if scores.totalQualifiedFlipsCount > 0 {
+		scores.totalScore = scores.totalFlipPoint / float32(scores.totalQualifiedFlipsCount)
+	}
+	return scores
+}
+
 func incSuccessfulInvites(validationAuthors *types.ValidationAuthors, god common.Address, invitee state.Identity, newState state.IdentityState) {
 	goodAuthors := validationAuthors.GoodAuthors
 	if invitee.State == state.Candidate && newState == state.Newbie && invitee.Inviter != nil {
@@ -913,13 +1133,19 @@ func determineIdentityBirthday(currentEpoch uint16, identity state.Identity, new
 }
 
 func determineNewIdentityState(identity state.Identity, shortScore, longScore, totalScore float32, totalQualifiedFlips uint32, missed, noQualShort, nonQualLong bool) state.IdentityState {
+	newState, _ := determineNewIdentityStateWithReason(identity, shortScore, longScore, totalScore, totalQualifiedFlips, missed, noQualShort, nonQualLong)
+	return newState
+}
+
+// determineNewIdentityStateWithReason returns the new identity state and the rule which has produced it
+func determineNewIdentityStateWithReason(identity state.Identity, shortScore, longScore, totalScore float32, totalQualifiedFlips uint32, missed, noQualShort, nonQualLong bool) (state.IdentityState, string) {
 
 	if !identity.HasDoneAllRequiredFlips() {
 		switch identity.State {
 		case state.Verified:
-			return state.Suspended
+			return state.Suspended, "required flips are not submitted"
 		default:
-			return state.Killed
+			return state.Killed, "required flips are not submitted"
 		}
 	}
 
@@ -927,70 +1153,76 @@ func determineNewIdentityState(identity state.Identity, shortScore, longScore, t
 
 	switch prevState {
 	case state.Undefined:
-		return state.Undefined
+		return state.Undefined, "identity is undefined"
 	case state.Invite:
-		return state.Killed
+		return state.Killed, "invite is not activated"
 	case state.Candidate:
 		if noQualShort || nonQualLong && shortScore >= MinShortScore {
-			return state.Candidate
+			return state.Candidate, "not enough qualified flips, the state is kept"
+		}
+		if missed {
+			return state.Killed, "validation is missed"
+		}
+		if shortScore < MinShortScore {
+			return state.Killed, "short session score is too low"
 		}
-		if missed || shortScore < MinShortScore || longScore < MinLongScore {
-			return state.Killed
+		if longScore < MinLongScore {
+			return state.Killed, "long session score is too low"
 		}
-		return state.Newbie
+		return state.Newbie, "short and long session scores are enough"
 	case state.Newbie:
 		if noQualShort ||
 			nonQualLong && totalQualifiedFlips > 10 && totalScore >= MinTotalScore && shortScore >= MinShortScore ||
 			nonQualLong && totalQualifiedFlips <= 10 && shortScore >= MinShortScore {
-			return state.Newbie
+			return state.Newbie, "not enough qualified flips, the state is kept"
 		}
 		if missed {
-			return state.Killed
+			return state.Killed, "validation is missed"
 		}
 		if totalQualifiedFlips > 10 && totalScore >= MinTotalScore && shortScore >= MinShortScore && longScore >= MinLongScore {
-			return state.Verified
+			return state.Verified, "total, short and long session scores are enough"
 		}
 		if totalQualifiedFlips <= 10 && shortScore >= MinShortScore && longScore >= 0.75 {
-			return state.Newbie
+			return state.Newbie, "scores are enough, but total qualified flips are not enough to become verified"
 		}
-		return state.Killed
+		return state.Killed, "scores are too low"
 	case state.Verified:
 		if noQualShort || nonQualLong && totalScore >= MinTotalScore && shortScore >= MinShortScore {
-			return state.Verified
+			return state.Verified, "not enough qualified flips, the state is kept"
 		}
 		if missed {
-			return state.Suspended
+			return state.Suspended, "validation is missed"
 		}
 		if totalQualifiedFlips > 10 && totalScore >= MinTotalScore && shortScore >= MinShortScore && longScore >= MinLongScore {
-			return state.Verified
+			return state.Verified, "total, short and long session scores are enough"
 		}
-		return state.Killed
+		return state.Killed, "scores or total qualified flips are too low"
 	case state.Suspended:
 		if noQualShort || nonQualLong && totalScore >= MinTotalScore && shortScore >= MinShortScore {
-			return state.Suspended
+			return state.Suspended, "not enough qualified flips, the state is kept"
 		}
 		if missed {
-			return state.Zombie
+			return state.Zombie, "validation is missed"
 		}
 		if totalScore >= MinTotalScore && shortScore >= MinShortScore && longScore >= MinLongScore {
-			return state.Verified
+			return state.Verified, "total, short and long session scores are enough"
 		}
-		return state.Killed
+		return state.Killed, "scores are too low"
 	case state.Zombie:
 		if noQualShort || nonQualLong && totalScore >= MinTotalScore && shortScore >= MinShortScore {
-			return state.Zombie
+			return state.Zombie, "not enough qualified flips, the state is kept"
 		}
 		if missed {
-			return state.Killed
+			return state.Killed, "validation is missed"
 		}
 		if totalScore >= MinTotalScore && shortScore >= MinShortScore {
-			return state.Verified
+			return state.Verified, "total and short session scores are enough"
 		}
-		return state.Killed
+		return state.Killed, "scores are too low"
 	case state.Killed:
-		return state.Killed
+		return state.Killed, "identity is killed"
 	}
-	return state.Undefined
+	return state.Undefined, "unknown identity state"
 }
 
 func (vc *ValidationCeremony) FlipKeyWordPairs() []int {
@@ -999,7 +1231,18 @@ func (vc *ValidationCeremony) FlipKeyWordPairs() []int {
 
 func (vc *ValidationCeremony) generateFlipKeyWordPairs(seed []byte) {
 	identity := vc.appState.State.GetIdentity(vc.secStore.GetAddress())
-	vc.flipKeyWordPairs, vc.flipKeyWordProof = vc.GeneratePairs(seed, common.WordDictionarySize, identity.GetTotalWordPairsCount())
+	pairs, proof, err := vc.GeneratePairs(seed, common.WordDictionarySize, identity.GetTotalWordPairsCount())
+	if err != nil {
+		vc.log.Error("Cannot generate flip key word pairs", "err", err)
+	}
+	vc.flipKeyWordPairs, vc.flipKeyWordProof = pairs, proof
+}
+
+func (vc *ValidationCeremony) GetFlipAuthor(cid []byte) (common.Address, bool) {
+	vc.flipAuthorMapLock.Lock()
+	defer vc.flipAuthorMapLock.Unlock()
+	author, ok := vc.flipAuthorMap[rlp.Hash(cid)]
+	return author, ok
 }
 
 func (vc *ValidationCeremony) GetFlipWords(cid []byte) (word1, word2 int, err error) {
@@ -1029,12 +1272,14 @@ func (vc *ValidationCeremony) GetFlipWords(cid []byte) (word1, word2 int, err er
 	return GetWords(seed, proof, identity.PubKey, common.WordDictionarySize, identity.GetTotalWordPairsCount(), pairId)
 }
 
-func getShortAnswersSalt(epoch uint16, secStore *secstore.SecStore) []byte {
+func getShortAnswersSalt(epoch uint16, secStore *secstore.SecStore) ([]byte, error) {
 	seed := []byte(fmt.Sprintf("short-answers-salt-%v", epoch))
-	hash := common.Hash(rlp.Hash(seed))
-	sig := secStore.Sign(hash.Bytes())
+	sig, err := secStore.SignSeed(seed)
+	if err != nil {
+		return nil, err
+	}
 	sha := sha3.Sum256(sig)
-	return sha[:]
+	return sha[:], nil
 }
 
 func (vc *ValidationCeremony) ShortSessionStarted() bool {
@@ -15,6 +15,34 @@ const (
 	MaxLongFlipSolvers    = 10
 )
 
+// assignFlips runs the flip lottery of both sessions, long session flips are chosen among short session ones
+func assignFlips(flipsPerAuthor map[int][][]byte, candidates []*candidate, flips [][]byte, seed []byte) (shortFlipsPerCandidate [][]int, longFlipsPerCandidate [][]int) {
+	shortFlipsPerCandidate = SortFlips(flipsPerAuthor, candidates, flips, int(common.ShortSessionFlipsCount()+common.ShortSessionExtraFlipsCount()), seed, false, nil)
+
+	chosenFlips := make(map[int]bool)
+	for _, a := range shortFlipsPerCandidate {
+		for _, f := range a {
+			chosenFlips[f] = true
+		}
+	}
+
+	// the seed is copied since it is reversed in place
+	longSeed := common.ReverseBytes(common.CopyBytes(seed))
+	longFlipsPerCandidate = SortFlips(flipsPerAuthor, candidates, flips, int(longSessionFlipsCount(len(flips), len(candidates))), longSeed, true, chosenFlips)
+	return shortFlipsPerCandidate, longFlipsPerCandidate
+}
+
+func longSessionFlipsCount(flips int, candidates int) uint {
+	if candidates == 0 {
+		return 1
+	}
+	count := uint(flips * common.LongSessionTesters / candidates)
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
 func SortFlips(flipsPerAuthor map[int][][]byte, candidates []*candidate, flips [][]byte, flipsPerAddr int, seed []byte, longSession bool, flipsToUse map[int]bool) (flipsPerCandidate [][]int) {
 
 	candidatesLen := len(candidates)
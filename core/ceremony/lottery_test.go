@@ -2,6 +2,7 @@ package ceremony
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/idena-network/idena-go/common"
 	"github.com/stretchr/testify/require"
@@ -61,6 +62,21 @@ func TestSortFlips_2(t *testing.T) {
 	require.Equal(t, flipsPerCandidateLongResult, flipsPerCandidateLong)
 }
 
+func TestAssignFlips(t *testing.T) {
+	seed := make([]byte, 8)
+	binary.LittleEndian.PutUint64(seed, 1000)
+
+	flipsPerAuthor, flips := makeFlips(7, 3)
+	candidates := makeCandidates(7)
+
+	short, long := assignFlips(flipsPerAuthor, candidates, flips, seed)
+	require.Equal(t, uint64(1000), binary.LittleEndian.Uint64(seed))
+
+	short2, long2 := assignFlips(flipsPerAuthor, candidates, flips, seed)
+	require.Equal(t, short, short2)
+	require.Equal(t, long, long2)
+}
+
 func TestHasRelation(t *testing.T) {
 	a := &candidate{
 		Generation: 4,
@@ -132,3 +148,69 @@ func makeCandidates(authors int) []*candidate {
 	}
 	return res
 }
+
+func TestLotteryData_Verify(t *testing.T) {
+	require := require.New(t)
+
+	seed := make([]byte, 8)
+	binary.LittleEndian.PutUint64(seed, 1000)
+
+	data := &LotteryData{
+		Epoch: 1,
+		Seed:  seed,
+	}
+	for i := 0; i < 10; i++ {
+		c := &LotteryCandidate{
+			Address: common.Address{byte(i + 1)},
+			Code:    random.GetRandomBytes(12),
+		}
+		for j := 0; j < 3; j++ {
+			c.Flips = append(c.Flips, random.GetRandomBytes(5))
+		}
+		data.Candidates = append(data.Candidates, c)
+	}
+	// the second candidate is in relation with the first one
+	data.Candidates[1].Code = data.Candidates[0].Code
+
+	self := data.Candidates[0].Address
+	result, err := data.Verify(self, nil, nil)
+	require.NoError(err)
+	require.True(result.ShortMatch)
+	require.True(result.LongMatch)
+	require.Equal([]common.Address{data.Candidates[1].Address}, result.RelatedAuthors)
+	require.Len(result.ShortFlips, int(common.ShortSessionFlipsCount()+common.ShortSessionExtraFlipsCount()))
+	for _, flip := range result.ShortFlips {
+		require.NotEqual(self, flip.Author)
+	}
+
+	toCids := func(flips []*LotteryFlip) [][]byte {
+		var res [][]byte
+		for _, flip := range flips {
+			res = append(res, flip.Cid)
+		}
+		return res
+	}
+	shortFlips, longFlips := toCids(result.ShortFlips), toCids(result.LongFlips)
+
+	result, err = data.Verify(self, shortFlips, longFlips)
+	require.NoError(err)
+	require.True(result.ShortMatch)
+	require.True(result.LongMatch)
+
+	result, err = data.Verify(self, shortFlips[1:], longFlips)
+	require.NoError(err)
+	require.False(result.ShortMatch)
+	require.True(result.LongMatch)
+
+	encoded, err := json.Marshal(data)
+	require.NoError(err)
+	decoded := new(LotteryData)
+	require.NoError(json.Unmarshal(encoded, decoded))
+	result, err = decoded.Verify(self, shortFlips, longFlips)
+	require.NoError(err)
+	require.True(result.ShortMatch)
+	require.True(result.LongMatch)
+
+	_, err = data.Verify(common.Address{0xff}, nil, nil)
+	require.Error(err)
+}
@@ -0,0 +1,198 @@
+package ceremony
+
+import (
+	"bytes"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// LotteryCandidate is a ceremony candidate with its flips, candidates should be kept in the state order
+// since flip indexes are derived from it
+type LotteryCandidate struct {
+	Address    common.Address `json:"address"`
+	Generation uint32         `json:"generation"`
+	Code       hexutil.Bytes  `json:"code"`
+	Flips      []FlipCid      `json:"flips"`
+}
+
+// LotteryData is everything needed to recompute the flip lottery of the epoch
+type LotteryData struct {
+	Epoch      uint16              `json:"epoch"`
+	Seed       hexutil.Bytes       `json:"seed"`
+	Candidates []*LotteryCandidate `json:"candidates"`
+}
+
+type LotteryFlip struct {
+	Cid    FlipCid        `json:"cid"`
+	Author common.Address `json:"author"`
+	// Related is true if the flip author is in genetic relation with the candidate, such flip is assigned
+	// only if there is no other suitable flip
+	Related bool `json:"related"`
+}
+
+type LotteryVerification struct {
+	Address    common.Address `json:"address"`
+	ShortFlips []*LotteryFlip `json:"shortFlips"`
+	LongFlips  []*LotteryFlip `json:"longFlips"`
+	// ShortMatch and LongMatch are true if given flip lists are equal to the recomputed ones
+	ShortMatch bool `json:"shortMatch"`
+	LongMatch  bool `json:"longMatch"`
+	// RelatedAuthors are candidates whose flips are excluded for the candidate because of the genetic relation
+	RelatedAuthors []common.Address `json:"relatedAuthors"`
+}
+
+// FlipCid is encoded as a cid string in json
+type FlipCid []byte
+
+func (c FlipCid) MarshalText() ([]byte, error) {
+	return []byte(cidString(c)), nil
+}
+
+func (c *FlipCid) UnmarshalText(input []byte) error {
+	if has0xPrefix(input) {
+		data, err := hexutil.Decode(string(input))
+		if err != nil {
+			return err
+		}
+		*c = data
+		return nil
+	}
+	parsed, err := cid.Decode(string(input))
+	if err != nil {
+		return err
+	}
+	*c = parsed.Bytes()
+	return nil
+}
+
+func has0xPrefix(input []byte) bool {
+	return len(input) >= 2 && input[0] == '0' && (input[1] == 'x' || input[1] == 'X')
+}
+
+// ReadLotteryData reads the lottery seed and ceremony candidates of the current state epoch
+func ReadLotteryData(appState *appstate.AppState, db dbm.DB) (*LotteryData, error) {
+	vc := &ValidationCeremony{
+		appState: appState,
+	}
+	candidates, _, _, flipsPerAuthor, _ := vc.getCandidatesAndFlips()
+	return newLotteryData(appState.State.Epoch(), db, candidates, flipsPerAuthor)
+}
+
+func newLotteryData(epoch uint16, db dbm.DB, candidates []*candidate, flipsPerAuthor map[int][][]byte) (*LotteryData, error) {
+	seed := database.NewEpochDb(db, epoch).ReadLotterySeed()
+	if seed == nil {
+		return nil, errors.Errorf("lottery seed of epoch %v is not found", epoch)
+	}
+	data := &LotteryData{
+		Epoch: epoch,
+		Seed:  seed,
+	}
+	for i, c := range candidates {
+		item := &LotteryCandidate{
+			Address:    c.Address,
+			Generation: c.Generation,
+			Code:       c.Code,
+			Flips:      []FlipCid{},
+		}
+		for _, f := range flipsPerAuthor[i] {
+			item.Flips = append(item.Flips, f)
+		}
+		data.Candidates = append(data.Candidates, item)
+	}
+	return data, nil
+}
+
+// Verify recomputes flips to solve of the address and compares them with the given lists,
+// nil lists are not compared
+func (d *LotteryData) Verify(address common.Address, shortFlips, longFlips [][]byte) (*LotteryVerification, error) {
+	if len(d.Seed) < 8 {
+		return nil, errors.New("lottery seed is invalid")
+	}
+	var candidates []*candidate
+	var flips [][]byte
+	flipsPerAuthor := make(map[int][][]byte)
+	authors := make(map[common.Hash]common.Address)
+	selfIdx := -1
+	for i, c := range d.Candidates {
+		candidates = append(candidates, &candidate{
+			Address:    c.Address,
+			Generation: c.Generation,
+			Code:       c.Code,
+		})
+		for _, cid := range c.Flips {
+			f := []byte(cid)
+			flips = append(flips, f)
+			flipsPerAuthor[i] = append(flipsPerAuthor[i], f)
+			authors[common.Hash(rlp.Hash(f))] = c.Address
+		}
+		if c.Address == address {
+			selfIdx = i
+		}
+	}
+	if selfIdx < 0 {
+		return nil, errors.Errorf("%v is not a ceremony candidate", address.Hex())
+	}
+
+	shortFlipsPerCandidate, longFlipsPerCandidate := assignFlips(flipsPerAuthor, candidates, flips, d.Seed)
+
+	self := candidates[selfIdx]
+	relatedAuthors := make(map[common.Address]bool)
+	result := &LotteryVerification{
+		Address:        address,
+		RelatedAuthors: []common.Address{},
+	}
+	for i, c := range candidates {
+		if i != selfIdx && len(flipsPerAuthor[i]) > 0 && hasRelation(self, c, GeneticRelationLength) {
+			relatedAuthors[c.Address] = true
+			result.RelatedAuthors = append(result.RelatedAuthors, c.Address)
+		}
+	}
+	toLotteryFlips := func(cids [][]byte) []*LotteryFlip {
+		var list []*LotteryFlip
+		for _, cid := range cids {
+			author := authors[common.Hash(rlp.Hash(cid))]
+			list = append(list, &LotteryFlip{
+				Cid:     cid,
+				Author:  author,
+				Related: relatedAuthors[author],
+			})
+		}
+		return list
+	}
+	expectedShortFlips := getFlipsToSolve(address, candidates, shortFlipsPerCandidate, flips)
+	expectedLongFlips := getFlipsToSolve(address, candidates, longFlipsPerCandidate, flips)
+	result.ShortFlips = toLotteryFlips(expectedShortFlips)
+	result.LongFlips = toLotteryFlips(expectedLongFlips)
+	result.ShortMatch = shortFlips == nil || flipListsEqual(expectedShortFlips, shortFlips)
+	result.LongMatch = longFlips == nil || flipListsEqual(expectedLongFlips, longFlips)
+	return result, nil
+}
+
+// LotteryData returns the lottery data of the current epoch, it is available since the flip lottery has started
+func (vc *ValidationCeremony) LotteryData() (*LotteryData, error) {
+	vc.flipAuthorMapLock.Lock()
+	candidates, flipsPerAuthor := vc.candidates, vc.flipsPerAuthor
+	vc.flipAuthorMapLock.Unlock()
+	if candidates == nil {
+		return nil, errors.New("ceremony candidates are not calculated yet")
+	}
+	return newLotteryData(vc.epoch, vc.db, candidates, flipsPerAuthor)
+}
+
+func flipListsEqual(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
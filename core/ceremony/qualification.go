@@ -122,8 +122,33 @@ func (q *qualification) qualifyFlips(totalFlipsCount uint, candidates []*candida
 	return result
 }
 
+type disqualifiedFlip struct {
+	flipIdx int
+	status  FlipStatus
+	reason  string
+}
+
+type candidateQualification struct {
+	point               float32
+	qualifiedFlipsCount uint32
+	flipAnswers         map[int]statsTypes.FlipAnswerStats
+	noQual              bool
+	// answersError explains why the answers have not been taken into account
+	answersError string
+	// disqualified are flips to solve which have not been counted as qualified for the candidate
+	disqualified []disqualifiedFlip
+}
+
 func (q *qualification) qualifyCandidate(candidate common.Address, flipQualificationMap map[int]FlipQualification,
 	flipsToSolve []int, shortSession bool, notApprovedFlips mapset.Set) (point float32, qualifiedFlipsCount uint32, flipAnswers map[int]statsTypes.FlipAnswerStats, noQual bool) {
+	res := q.qualifyCandidateFlips(candidate, flipQualificationMap, flipsToSolve, shortSession, notApprovedFlips)
+	return res.point, res.qualifiedFlipsCount, res.flipAnswers, res.noQual
+}
+
+func (q *qualification) qualifyCandidateFlips(candidate common.Address, flipQualificationMap map[int]FlipQualification,
+	flipsToSolve []int, shortSession bool, notApprovedFlips mapset.Set) *candidateQualification {
+
+	res := &candidateQualification{}
 
 	var answerBytes []byte
 	if shortSession {
@@ -134,7 +159,8 @@ func (q *qualification) qualifyCandidate(candidate common.Address, flipQualifica
 
 	// candidate didn't send answers
 	if answerBytes == nil {
-		return 0, 0, nil, false
+		res.answersError = "answers are not sent"
+		return res
 	}
 
 	if shortSession {
@@ -142,12 +168,16 @@ func (q *qualification) qualifyCandidate(candidate common.Address, flipQualifica
 		flipsCount := uint32(math.MinInt(int(common.ShortSessionFlipsCount()), len(flipsToSolve)))
 		// can't parse
 		if attachment == nil {
-			return 0, flipsCount, nil, false
+			res.qualifiedFlipsCount = flipsCount
+			res.answersError = "answers cannot be parsed"
+			return res
 		}
 		hash := q.epochDb.GetAnswerHash(candidate)
 		answerBytes = attachment.Answers
 		if answerBytes == nil || hash != rlp.Hash(append(answerBytes, attachment.Salt...)) {
-			return 0, flipsCount, nil, false
+			res.qualifiedFlipsCount = flipsCount
+			res.answersError = "answers do not match the submitted hash"
+			return res
 		}
 	}
 
@@ -162,7 +192,7 @@ func (q *qualification) qualifyCandidate(candidate common.Address, flipQualifica
 			}
 		}
 	}
-	flipAnswers = make(map[int]statsTypes.FlipAnswerStats, len(flipsToSolve)+availableExtraFlips)
+	res.flipAnswers = make(map[int]statsTypes.FlipAnswerStats, len(flipsToSolve)+availableExtraFlips)
 
 	for i, flipIdx := range flipsToSolve {
 		qual := flipQualificationMap[flipIdx]
@@ -178,35 +208,57 @@ func (q *qualification) qualifyCandidate(candidate common.Address, flipQualifica
 			}
 		}
 
+		prevQualifiedFlipsCount := res.qualifiedFlipsCount
 		var answerPoint float32
 		switch status {
 		case Qualified:
 			if qual.answer == answer {
 				answerPoint = 1
 			}
-			qualifiedFlipsCount += 1
+			res.qualifiedFlipsCount += 1
 		case WeaklyQualified:
 			switch {
 			case qual.answer == answer:
 				answerPoint = 1
-				qualifiedFlipsCount += 1
+				res.qualifiedFlipsCount += 1
 				break
 			case answer == types.None:
-				qualifiedFlipsCount += 1
+				res.qualifiedFlipsCount += 1
 				break
 			case qual.answer != types.Inappropriate:
 				answerPoint = 0.5
-				qualifiedFlipsCount += 1
+				res.qualifiedFlipsCount += 1
 			}
 		}
-		point += answerPoint
-		flipAnswers[flipIdx] = statsTypes.FlipAnswerStats{
+		if res.qualifiedFlipsCount == prevQualifiedFlipsCount {
+			res.disqualified = append(res.disqualified, disqualifiedFlip{
+				flipIdx: flipIdx,
+				status:  status,
+				reason:  disqualificationReason(qual.status, status),
+			})
+		}
+		res.point += answerPoint
+		res.flipAnswers[flipIdx] = statsTypes.FlipAnswerStats{
 			Respondent: candidate,
 			Answer:     answer,
 			Point:      answerPoint,
 		}
 	}
-	return point, qualifiedFlipsCount, flipAnswers, qualifiedFlipsCount == 0
+	res.noQual = res.qualifiedFlipsCount == 0
+	return res
+}
+
+func disqualificationReason(baseStatus FlipStatus, status FlipStatus) string {
+	switch {
+	case baseStatus != NotQualified && status == NotQualified:
+		return "flip author is not approved and the flip is not answered"
+	case status == NotQualified:
+		return "flip is not qualified"
+	case status == QualifiedByNone:
+		return "flip is qualified by none answers"
+	default:
+		return "flip is weakly qualified as inappropriate and the answer differs"
+	}
 }
 
 func (q *qualification) GetProof(addr common.Address) []byte {
@@ -0,0 +1,136 @@
+package ceremony
+
+import (
+	mapset "github.com/deckarep/golang-set"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/log"
+	"github.com/pkg/errors"
+	dbm "github.com/tendermint/tm-db"
+)
+
+type SimulatedIdentity struct {
+	Address     common.Address
+	Candidate   bool
+	Approved    bool
+	Missed      bool
+	ShortPoint  float32
+	ShortFlips  uint32
+	ShortScore  float32
+	LongPoint   float32
+	LongFlips   uint32
+	LongScore   float32
+	TotalScore  float32
+	TotalFlips  uint32
+	NoQualShort bool
+	NoQualLong  bool
+	PrevState   state.IdentityState
+	NewState    state.IdentityState
+	Birthday    uint16
+}
+
+type SimulationResult struct {
+	Epoch      uint16
+	Candidates int
+	Flips      int
+	// Failed is true if nobody is validated, the chain keeps identity states unchanged in this case
+	Failed     bool
+	Identities []*SimulatedIdentity
+	Authors    *types.ValidationAuthors
+}
+
+// SimulateEpoch re-runs flip qualification and identity state determination of the current state epoch
+// using answers, evidence maps and the lottery seed recorded in the epoch db, the state is not modified
+func SimulateEpoch(appState *appstate.AppState, db dbm.DB) (*SimulationResult, error) {
+	epoch := appState.State.Epoch()
+	epochDb := database.NewEpochDb(db, epoch)
+	seed := epochDb.ReadLotterySeed()
+	if seed == nil {
+		return nil, errors.Errorf("lottery seed of epoch %v is not found, the epoch data is cleared when the epoch is applied", epoch)
+	}
+
+	vc := &ValidationCeremony{
+		appState:      appState,
+		db:            db,
+		epoch:         epoch,
+		epochDb:       epochDb,
+		qualification: NewQualification(epochDb),
+		log:           log.New("component", "simulation"),
+	}
+	vc.qualification.restore()
+	vc.distributeFlips(seed)
+
+	approvedCandidates := appState.EvidenceMap.CalculateApprovedCandidates(vc.getCandidatesAddresses(), epochDb.ReadEvidenceMaps())
+	approvedCandidatesSet := mapset.NewSet()
+	for _, item := range approvedCandidates {
+		approvedCandidatesSet.Add(item)
+	}
+
+	flipQualification := vc.qualification.qualifyFlips(uint(len(vc.flips)), vc.candidates, vc.longFlipsPerCandidate)
+	flipQualificationMap := make(map[int]FlipQualification)
+	for i, item := range flipQualification {
+		flipQualificationMap[i] = item
+	}
+	validationAuthors := new(types.ValidationAuthors)
+	validationAuthors.BadAuthors, validationAuthors.GoodAuthors = vc.analizeAuthors(flipQualification)
+	notApprovedFlips := vc.getNotApprovedFlips(approvedCandidatesSet)
+	god := appState.State.GodAddress()
+
+	result := &SimulationResult{
+		Epoch:      epoch,
+		Candidates: len(vc.candidates),
+		Flips:      len(vc.flips),
+		Authors:    validationAuthors,
+		Failed:     true,
+	}
+
+	for idx, candidate := range vc.candidates {
+		addr := candidate.Address
+		scores := vc.calculateCandidateScores(appState, idx, flipQualificationMap, approvedCandidatesSet, notApprovedFlips)
+		identity := appState.State.GetIdentity(addr)
+		newIdentityState := determineNewIdentityState(identity, scores.shortScore, scores.longScore, scores.totalScore,
+			scores.totalQualifiedFlipsCount, scores.missed, scores.noQualShort, scores.noQualLong)
+
+		incSuccessfulInvites(validationAuthors, god, identity, newIdentityState)
+
+		if newIdentityState == state.Verified || newIdentityState == state.Newbie {
+			result.Failed = false
+		}
+		result.Identities = append(result.Identities, &SimulatedIdentity{
+			Address:     addr,
+			Candidate:   true,
+			Approved:    scores.approved,
+			Missed:      scores.missed,
+			ShortPoint:  scores.shortFlipPoint,
+			ShortFlips:  scores.shortQualifiedFlipsCount,
+			ShortScore:  scores.shortScore,
+			LongPoint:   scores.longFlipPoint,
+			LongFlips:   scores.longQualifiedFlipsCount,
+			LongScore:   scores.longScore,
+			TotalScore:  scores.totalScore,
+			TotalFlips:  scores.totalQualifiedFlipsCount,
+			NoQualShort: scores.noQualShort,
+			NoQualLong:  scores.noQualLong,
+			PrevState:   identity.State,
+			NewState:    newIdentityState,
+			Birthday:    determineIdentityBirthday(epoch, identity, newIdentityState),
+		})
+	}
+
+	for _, addr := range vc.nonCandidates {
+		identity := appState.State.GetIdentity(addr)
+		newIdentityState := determineNewIdentityState(identity, 0, 0, 0, 0, true, false, false)
+		result.Identities = append(result.Identities, &SimulatedIdentity{
+			Address:   addr,
+			Missed:    true,
+			PrevState: identity.State,
+			NewState:  newIdentityState,
+			Birthday:  determineIdentityBirthday(epoch, identity, newIdentityState),
+		})
+	}
+
+	return result, nil
+}
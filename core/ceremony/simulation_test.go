@@ -0,0 +1,58 @@
+package ceremony
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/database"
+	"github.com/stretchr/testify/require"
+	db "github.com/tendermint/tm-db"
+	"testing"
+)
+
+func TestSimulateEpoch(t *testing.T) {
+	r := require.New(t)
+	memDb := db.NewMemDB()
+	appState := appstate.NewAppState(memDb, eventbus.New())
+
+	verified := common.Address{0x1}
+	candidate := common.Address{0x2}
+	invite := common.Address{0x3}
+	appState.State.SetState(verified, state.Verified)
+	appState.State.SetState(candidate, state.Candidate)
+	appState.State.SetState(invite, state.Invite)
+	appState.State.SetGlobalEpoch(3)
+	_, _, err := appState.State.Commit(true)
+	r.NoError(err)
+
+	_, err = SimulateEpoch(appState, memDb)
+	r.Error(err, "lottery seed is required")
+
+	database.NewEpochDb(memDb, 3).WriteLotterySeed([]byte{0x1, 0x2, 0x3})
+	result, err := SimulateEpoch(appState, memDb)
+	r.NoError(err)
+
+	r.Equal(uint16(3), result.Epoch)
+	r.Equal(2, result.Candidates)
+	r.Equal(0, result.Flips)
+	r.True(result.Failed)
+
+	identities := make(map[common.Address]*SimulatedIdentity)
+	for _, identity := range result.Identities {
+		identities[identity.Address] = identity
+	}
+	r.Len(identities, 3)
+
+	r.True(identities[verified].Candidate)
+	r.False(identities[verified].Approved)
+	r.True(identities[verified].Missed)
+	r.Equal(state.Verified, identities[verified].PrevState)
+	r.Equal(state.Suspended, identities[verified].NewState)
+
+	r.True(identities[candidate].Candidate)
+	r.Equal(state.Killed, identities[candidate].NewState)
+
+	r.False(identities[invite].Candidate)
+	r.Equal(state.Killed, identities[invite].NewState)
+}
@@ -15,8 +15,11 @@ const (
 	m = 1 << 16
 )
 
-func (vc *ValidationCeremony) GeneratePairs(seed []byte, dictionarySize, pairCount int) (nums []int, proof []byte) {
-	hash, proof := vc.secStore.VrfEvaluate(seed)
+func (vc *ValidationCeremony) GeneratePairs(seed []byte, dictionarySize, pairCount int) (nums []int, proof []byte, err error) {
+	hash, proof, err := vc.secStore.VrfEvaluate(seed)
+	if err != nil {
+		return nil, nil, err
+	}
 	rnd := generatePseudoRndSeed(hash, dictionarySize)
 	pairs := mapset.NewSet()
 	for i := 0; i < pairCount; i++ {
@@ -24,7 +27,7 @@ func (vc *ValidationCeremony) GeneratePairs(seed []byte, dictionarySize, pairCou
 		num1, num2, rnd = nextPair(rnd, dictionarySize, pairCount, pairs)
 		nums = append(nums, num1, num2)
 	}
-	return nums, proof
+	return nums, proof, nil
 }
 
 func CheckPair(seed []byte, proof []byte, pubKeyData []byte, dictionarySize, pairCount, num1, num2 int) bool {
@@ -30,7 +30,8 @@ func Test_GeneratePairs(t *testing.T) {
 		{1, 1, false},
 		{3, 4, false},
 	} {
-		nums, proof := vc.GeneratePairs([]byte("data"), tc.dictionarySize, tc.pairCount)
+		nums, proof, err := vc.GeneratePairs([]byte("data"), tc.dictionarySize, tc.pairCount)
+		require.NoError(t, err)
 
 		require.Equal(t, tc.pairCount*2, len(nums))
 		require.NotNil(t, proof)
@@ -71,7 +72,8 @@ func Test_CheckPair(t *testing.T) {
 	seed := []byte("data1")
 	dictionarySize := 3300
 	pairCount := 9
-	nums, proof := vc.GeneratePairs(seed, dictionarySize, pairCount)
+	nums, proof, err := vc.GeneratePairs(seed, dictionarySize, pairCount)
+	require.NoError(t, err)
 
 	require.True(t, CheckPair(seed, proof, pk, dictionarySize, pairCount, nums[0], nums[1]))
 	require.True(t, CheckPair(seed, proof, pk, dictionarySize, pairCount, nums[2], nums[3]))
@@ -95,7 +97,8 @@ func Test_GetWords(t *testing.T) {
 	seed := []byte("data1")
 	dictionarySize := 3300
 	pairCount := 9
-	nums, proof := vc.GeneratePairs(seed, dictionarySize, pairCount)
+	nums, proof, err := vc.GeneratePairs(seed, dictionarySize, pairCount)
+	require.NoError(err)
 
 	w1, w2, _ := GetWords(seed, proof, pk, dictionarySize, pairCount, 1)
 	require.Equal(nums[2], w1)
@@ -105,7 +108,7 @@ func Test_GetWords(t *testing.T) {
 	require.Equal(nums[16], w1)
 	require.Equal(nums[17], w2)
 
-	w1, w2, err := GetWords(seed, proof, pk, dictionarySize, pairCount, 15)
+	w1, w2, err = GetWords(seed, proof, pk, dictionarySize, pairCount, 15)
 	require.Error(err, "out of bounds pair index should throw error")
 
 	_, _, err = GetWords([]byte("data2"), proof, pk, dictionarySize, pairCount, 1)
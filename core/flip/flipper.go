@@ -46,6 +46,7 @@ type Flipper struct {
 	secStore         *secstore.SecStore
 	flips            map[common.Hash]*IpfsFlip
 	flipReadiness    map[common.Hash]bool
+	loadingErrors    map[common.Hash]string
 	appState         *appstate.AppState
 	txpool           *mempool.TxPool
 	loadingCtx       context.Context
@@ -76,6 +77,7 @@ func NewFlipper(db dbm.DB, ipfsProxy ipfs.Proxy, keyspool *mempool.KeysPool, txp
 		secStore:         secStore,
 		flips:            make(map[common.Hash]*IpfsFlip),
 		flipReadiness:    make(map[common.Hash]bool),
+		loadingErrors:    make(map[common.Hash]string),
 		appState:         appState,
 		loadingCtx:       ctx,
 		cancelLoadingCtx: cancel,
@@ -184,7 +186,14 @@ func (fp *Flipper) AddNewFlip(flip *types.Flip, local bool) error {
 
 func (fp *Flipper) PrepareFlip(hex []byte) (cid.Cid, []byte, error) {
 
+	if err := ValidateFlip(hex); err != nil {
+		return cid.Cid{}, nil, errors.Wrap(err, "invalid flip")
+	}
+
 	encryptionKey := fp.GetFlipEncryptionKey()
+	if encryptionKey == nil {
+		return cid.Cid{}, nil, errors.New("flip key is missing")
+	}
 
 	encrypted, err := ecies.Encrypt(rand.Reader, &encryptionKey.PublicKey, hex, nil, nil)
 
@@ -252,27 +261,57 @@ func (fp *Flipper) GetFlipEncryptionKey() *ecies.PrivateKey {
 		return fp.flipKey
 	}
 
-	seed := []byte(fmt.Sprintf("flip-key-for-epoch-%v", fp.appState.State.Epoch()))
+	key, err := fp.flipEncryptionKey(fp.appState.State.Epoch())
+	if err != nil {
+		fp.log.Error("Cannot generate flip key", "err", err)
+		return nil
+	}
+	fp.flipKey = key
 
-	hash := common.Hash(rlp.Hash(seed))
+	return fp.flipKey
+}
 
-	sig := fp.secStore.Sign(hash.Bytes())
+func (fp *Flipper) flipEncryptionKey(epoch uint16) (*ecies.PrivateKey, error) {
+	seed := []byte(fmt.Sprintf("flip-key-for-epoch-%v", epoch))
 
-	flipKey, _ := crypto.GenerateKeyFromSeed(bytes.NewReader(sig))
+	sig, err := fp.secStore.SignSeed(seed)
+	if err != nil {
+		return nil, err
+	}
 
-	fp.flipKey = ecies.ImportECDSA(flipKey)
+	flipKey, err := crypto.GenerateKeyFromSeed(bytes.NewReader(sig))
+	if err != nil {
+		return nil, err
+	}
 
-	return fp.flipKey
+	return ecies.ImportECDSA(flipKey), nil
 }
 
-func (fp *Flipper) Load(cids [][]byte) {
+// DecryptOwnFlip fetches the flip submitted by the node during the given epoch and decrypts it
+func (fp *Flipper) DecryptOwnFlip(cid []byte, epoch uint16) ([]byte, error) {
+	ipfsFlip, err := fp.GetRawFlip(cid)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Compare(ipfsFlip.PubKey, fp.secStore.GetPubKey()) != 0 {
+		return nil, errors.New("flip is not own")
+	}
+	key, err := fp.flipEncryptionKey(epoch)
+	if err != nil {
+		return nil, err
+	}
+	return key.Decrypt(ipfsFlip.Data, nil, nil)
+}
+
+// Load fetches the flips from ipfs until all of them are loaded, returns false if loading has been cancelled
+func (fp *Flipper) Load(cids [][]byte) bool {
 	ctx := fp.loadingCtx
 
 	for len(cids) > 0 {
 
 		select {
 		case <-ctx.Done():
-			return
+			return false
 		default:
 		}
 
@@ -285,6 +324,7 @@ func (fp *Flipper) Load(cids [][]byte) {
 
 		if err != nil {
 			fp.log.Warn("Can't get flip by cid", "cid", cid.String(), "err", err)
+			fp.setLoadingError(key, err)
 			cids = append(cids, key)
 			continue
 		}
@@ -294,6 +334,7 @@ func (fp *Flipper) Load(cids [][]byte) {
 			oldIpfsFlip := new(IpfsFlipOld)
 			if err2 := rlp.Decode(bytes.NewReader(data), oldIpfsFlip); err2 != nil {
 				fp.log.Warn("Can't decode flip", "cid", cid.String(), "err", err)
+				fp.setLoadingError(key, err)
 				continue
 			} else {
 				ipfsFlip.Data = oldIpfsFlip.Data
@@ -302,10 +343,12 @@ func (fp *Flipper) Load(cids [][]byte) {
 		}
 		fp.mutex.Lock()
 		fp.flips[common.Hash(rlp.Hash(key))] = ipfsFlip
+		delete(fp.loadingErrors, common.Hash(rlp.Hash(key)))
 		fp.mutex.Unlock()
 	}
 	fp.log.Info("All flips were loaded")
 	fp.hasFlips = true
+	return true
 }
 
 func (fp *Flipper) Reset() {
@@ -316,6 +359,7 @@ func (fp *Flipper) Reset() {
 	fp.hasFlips = false
 	fp.flips = make(map[common.Hash]*IpfsFlip)
 	fp.flipReadiness = make(map[common.Hash]bool)
+	fp.loadingErrors = make(map[common.Hash]string)
 	fp.keyspool.Clear()
 	fp.Initialize()
 	fp.flipKey = nil
@@ -350,6 +394,52 @@ func (fp *Flipper) IsFlipReady(cid []byte) bool {
 	return isReady
 }
 
+type FlipReadiness struct {
+	// Fetched is true if the encrypted flip has been downloaded from ipfs
+	Fetched bool
+	// KeyReceived is true if the flip key of the author has arrived
+	KeyReceived bool
+	// Ready is true if the flip has been decrypted
+	Ready bool
+	Error string
+}
+
+// GetFlipReadiness reports the downloading and decryption progress of the flip created by the author
+func (fp *Flipper) GetFlipReadiness(cid []byte, author common.Address) FlipReadiness {
+	hash := common.Hash(rlp.Hash(cid))
+
+	fp.mutex.Lock()
+	flip := fp.flips[hash]
+	loadingError := fp.loadingErrors[hash]
+	fp.mutex.Unlock()
+
+	readiness := FlipReadiness{
+		Fetched: flip != nil,
+		Error:   loadingError,
+	}
+	if author == fp.secStore.GetAddress() {
+		readiness.KeyReceived = true
+	} else {
+		readiness.KeyReceived = fp.keyspool.GetFlipKey(author) != nil
+	}
+	if !readiness.Fetched || !readiness.KeyReceived {
+		return readiness
+	}
+	readiness.Ready = fp.IsFlipReady(cid)
+	if !readiness.Ready {
+		if _, err := fp.GetFlip(cid); err != nil {
+			readiness.Error = err.Error()
+		}
+	}
+	return readiness
+}
+
+func (fp *Flipper) setLoadingError(cid []byte, err error) {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+	fp.loadingErrors[common.Hash(rlp.Hash(cid))] = err.Error()
+}
+
 func (fp *Flipper) UnpinFlip(flipCid []byte) {
 	fp.ipfsProxy.Unpin(flipCid)
 }
@@ -0,0 +1,172 @@
+package flip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	FlipFormatVersion = 1
+
+	CompressionNone = 0
+	CompressionGzip = 1
+
+	FlipImagesCount  = 4
+	FlipOrdersCount  = 2
+	MaxFlipImageSize = 1024
+	// maxFlipPayloadSize limits the decompressed payload to prevent decompression bombs
+	maxFlipPayloadSize = MaxFlipSize * 4
+)
+
+// FlipContainer is the versioned plain flip, payload is the rlp encoded FlipContent which is optionally compressed
+type FlipContainer struct {
+	Version     uint8
+	Compression uint8
+	Payload     []byte
+}
+
+// FlipContent is the decoded flip, flips submitted before the versioned container contain the rlp encoded content only
+type FlipContent struct {
+	Images [][]byte
+	// Orders are left and right permutations of image indexes
+	Orders [][]uint
+}
+
+// EncodeFlip builds the versioned container of the flip content
+func EncodeFlip(content *FlipContent, compression uint8) ([]byte, error) {
+	payload, err := rlp.EncodeToBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	switch compression {
+	case CompressionNone:
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		payload = buf.Bytes()
+	default:
+		return nil, errors.Errorf("unknown flip compression %v", compression)
+	}
+	return rlp.EncodeToBytes(&FlipContainer{
+		Version:     FlipFormatVersion,
+		Compression: compression,
+		Payload:     payload,
+	})
+}
+
+// DecodeFlip decodes both the versioned container and the legacy flip which is the rlp encoded content
+func DecodeFlip(data []byte) (*FlipContent, error) {
+	container := new(FlipContainer)
+	if err := rlp.DecodeBytes(data, container); err != nil {
+		content := new(FlipContent)
+		if err2 := rlp.DecodeBytes(data, content); err2 != nil {
+			return nil, errors.Wrap(err, "cannot decode flip")
+		}
+		return content, nil
+	}
+	if container.Version == 0 || container.Version > FlipFormatVersion {
+		return nil, errors.Errorf("unsupported flip version %v", container.Version)
+	}
+	payload, err := decompress(container.Payload, container.Compression)
+	if err != nil {
+		return nil, err
+	}
+	content := new(FlipContent)
+	if err := rlp.DecodeBytes(payload, content); err != nil {
+		return nil, errors.Wrap(err, "cannot decode flip content")
+	}
+	return content, nil
+}
+
+// ValidateFlip checks the flip structure, orders and dimensions of images
+func ValidateFlip(data []byte) error {
+	content, err := DecodeFlip(data)
+	if err != nil {
+		return err
+	}
+	if len(content.Images) != FlipImagesCount {
+		return errors.Errorf("flip should contain %v images, actual %v", FlipImagesCount, len(content.Images))
+	}
+	for i, img := range content.Images {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(img))
+		if err != nil {
+			return errors.Wrapf(err, "image %v cannot be decoded", i)
+		}
+		if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > MaxFlipImageSize || cfg.Height > MaxFlipImageSize {
+			return errors.Errorf("image %v has invalid dimensions %vx%v, max expected %vx%v", i, cfg.Width, cfg.Height,
+				MaxFlipImageSize, MaxFlipImageSize)
+		}
+	}
+	if len(content.Orders) != FlipOrdersCount {
+		return errors.Errorf("flip should contain %v orders, actual %v", FlipOrdersCount, len(content.Orders))
+	}
+	for i, order := range content.Orders {
+		if !isPermutation(order, len(content.Images)) {
+			return errors.Errorf("order %v is not a permutation of images", i)
+		}
+	}
+	if equalOrders(content.Orders[0], content.Orders[1]) {
+		return errors.New("left and right orders should differ")
+	}
+	return nil
+}
+
+func decompress(payload []byte, compression uint8) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return payload, nil
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot decompress flip")
+		}
+		defer r.Close()
+		data, err := ioutil.ReadAll(io.LimitReader(r, maxFlipPayloadSize+1))
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot decompress flip")
+		}
+		if len(data) > maxFlipPayloadSize {
+			return nil, errors.Errorf("decompressed flip is too big, max expected size %v", maxFlipPayloadSize)
+		}
+		return data, nil
+	default:
+		return nil, errors.Errorf("unknown flip compression %v", compression)
+	}
+}
+
+func isPermutation(order []uint, n int) bool {
+	if len(order) != n {
+		return false
+	}
+	seen := make([]bool, n)
+	for _, idx := range order {
+		if idx >= uint(n) || seen[idx] {
+			return false
+		}
+		seen[idx] = true
+	}
+	return true
+}
+
+func equalOrders(a, b []uint) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package flip
+
+import (
+	"bytes"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func makeImage(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func makeFlipContent(t *testing.T) *FlipContent {
+	content := &FlipContent{
+		Orders: [][]uint{{0, 1, 2, 3}, {3, 2, 1, 0}},
+	}
+	for i := 0; i < FlipImagesCount; i++ {
+		content.Images = append(content.Images, makeImage(t, 440, 330))
+	}
+	return content
+}
+
+func TestEncodeFlip(t *testing.T) {
+	require := require.New(t)
+	content := makeFlipContent(t)
+
+	for _, compression := range []uint8{CompressionNone, CompressionGzip} {
+		data, err := EncodeFlip(content, compression)
+		require.NoError(err)
+		decoded, err := DecodeFlip(data)
+		require.NoError(err)
+		require.Equal(content, decoded)
+		require.NoError(ValidateFlip(data))
+	}
+
+	_, err := EncodeFlip(content, 5)
+	require.Error(err)
+
+	// legacy flips contain rlp encoded content only
+	legacy, err := rlp.EncodeToBytes(content)
+	require.NoError(err)
+	decoded, err := DecodeFlip(legacy)
+	require.NoError(err)
+	require.Equal(content, decoded)
+	require.NoError(ValidateFlip(legacy))
+
+	unknownVersion, _ := rlp.EncodeToBytes(&FlipContainer{Version: FlipFormatVersion + 1, Payload: legacy})
+	_, err = DecodeFlip(unknownVersion)
+	require.Error(err)
+
+	_, err = DecodeFlip([]byte{0x1, 0x2, 0x3})
+	require.Error(err)
+}
+
+func TestValidateFlip(t *testing.T) {
+	require := require.New(t)
+
+	validate := func(change func(content *FlipContent)) error {
+		content := makeFlipContent(t)
+		change(content)
+		data, err := EncodeFlip(content, CompressionNone)
+		require.NoError(err)
+		return ValidateFlip(data)
+	}
+
+	require.NoError(validate(func(content *FlipContent) {}))
+	require.Error(validate(func(content *FlipContent) {
+		content.Images = content.Images[1:]
+	}))
+	require.Error(validate(func(content *FlipContent) {
+		content.Images[0] = []byte{0x1, 0x2}
+	}))
+	require.Error(validate(func(content *FlipContent) {
+		content.Images[1] = makeImage(t, MaxFlipImageSize+1, 10)
+	}))
+	require.Error(validate(func(content *FlipContent) {
+		content.Orders = content.Orders[:1]
+	}))
+	require.Error(validate(func(content *FlipContent) {
+		content.Orders[0] = []uint{0, 1, 1, 3}
+	}))
+	require.Error(validate(func(content *FlipContent) {
+		content.Orders[1] = []uint{0, 1, 2, 4}
+	}))
+	require.Error(validate(func(content *FlipContent) {
+		content.Orders[1] = []uint{0, 1, 2, 3}
+	}))
+}
@@ -0,0 +1,268 @@
+package invites
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/crypto/ecies"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/events"
+	"github.com/idena-network/idena-go/log"
+	"github.com/idena-network/idena-go/secstore"
+	"github.com/pkg/errors"
+	dbm "github.com/tendermint/tm-db"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Pending invite tx is not mined yet
+	Pending = "pending"
+	// Issued invite is mined and waits for the activation
+	Issued = "issued"
+	// Activated invitee is a candidate of the upcoming validation
+	Activated = "activated"
+	// Validated invitee has passed the validation
+	Validated = "validated"
+	// Expired invite has not been activated before the validation
+	Expired = "expired"
+	// Killed invitee has failed the validation
+	Killed = "killed"
+	// Revoked invitee has been killed by the inviter
+	Revoked = "revoked"
+
+	// ExpirationWarningPeriod is the period before the validation since not activated invites are flagged as expiring
+	ExpirationWarningPeriod = time.Hour * 24
+
+	encryptionKeySeed = "invites-encryption-key"
+)
+
+// Invite is the saved invite with the current invitee status derived from the state
+type Invite struct {
+	*types.SavedInvite
+	// Address is the current invitee address
+	Address      common.Address
+	State        state.IdentityState
+	Status       string
+	ExpiresAt    time.Time
+	ExpiringSoon bool
+}
+
+// Manager keeps invites issued by the node and tracks their activation, validation and revocation
+type Manager struct {
+	repo     *database.Repo
+	appState *appstate.AppState
+	secStore *secstore.SecStore
+	bus      eventbus.Bus
+	log      log.Logger
+	mutex    sync.Mutex
+}
+
+func NewManager(db dbm.DB, appState *appstate.AppState, secStore *secstore.SecStore, bus eventbus.Bus) *Manager {
+	return &Manager{
+		repo:     database.NewRepo(db),
+		appState: appState,
+		secStore: secStore,
+		bus:      bus,
+		log:      log.New("component", "invites"),
+	}
+}
+
+func (m *Manager) Start() {
+	_ = m.bus.Subscribe(events.AddBlockEventID, func(e eventbus.Event) {
+		newBlockEvent := e.(*events.NewBlockEvent)
+		m.handleBlock(newBlockEvent.Block)
+	})
+}
+
+// Add saves the sent invite, the generated receiver key is encrypted before saving, key is nil if the receiver is given
+func (m *Manager) Add(receiver common.Address, txHash common.Hash, amount *big.Int, key *ecdsa.PrivateKey) error {
+	invite := &types.SavedInvite{
+		Receiver:  receiver,
+		Inviter:   m.secStore.GetAddress(),
+		TxHash:    txHash,
+		Epoch:     m.appState.State.Epoch(),
+		Amount:    amount,
+		Timestamp: uint64(time.Now().Unix()),
+	}
+	if key != nil {
+		encryptionKey, err := m.encryptionKey()
+		if err != nil {
+			return errors.Wrap(err, "cannot generate encryption key")
+		}
+		encrypted, err := ecies.Encrypt(rand.Reader, &encryptionKey.PublicKey, crypto.FromECDSA(key), nil, nil)
+		if err != nil {
+			return errors.Wrap(err, "cannot encrypt invite key")
+		}
+		invite.EncryptedKey = encrypted
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.repo.WriteInvite(invite)
+	return nil
+}
+
+// Invites returns saved invites of the current coinbase, the newest are first
+func (m *Manager) Invites() []*Invite {
+	inviter := m.secStore.GetAddress()
+	var res []*Invite
+	for _, saved := range m.repo.GetInvites() {
+		if saved.Inviter == inviter {
+			res = append(res, m.view(saved))
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp > res[j].Timestamp
+	})
+	return res
+}
+
+func (m *Manager) Invite(receiver common.Address) (*Invite, error) {
+	saved := m.repo.ReadInvite(receiver)
+	if saved == nil {
+		return nil, errors.New("invite is not found")
+	}
+	return m.view(saved), nil
+}
+
+// Key returns the decrypted receiver key of the invite
+func (m *Manager) Key(receiver common.Address) (*ecdsa.PrivateKey, error) {
+	saved := m.repo.ReadInvite(receiver)
+	if saved == nil {
+		return nil, errors.New("invite is not found")
+	}
+	if len(saved.EncryptedKey) == 0 {
+		return nil, errors.New("invite key was not generated by the node")
+	}
+	if saved.Inviter != m.secStore.GetAddress() {
+		return nil, errors.New("invite is issued by another coinbase")
+	}
+	encryptionKey, err := m.encryptionKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot generate encryption key")
+	}
+	data, err := encryptionKey.Decrypt(saved.EncryptedKey, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot decrypt invite key")
+	}
+	return crypto.ToECDSA(data)
+}
+
+// RevocableAddress returns the current invitee address which can be killed by KillInviteeTx
+func (m *Manager) RevocableAddress(receiver common.Address) (common.Address, error) {
+	invite, err := m.Invite(receiver)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if invite.Status != Issued && invite.Status != Activated {
+		return common.Address{}, errors.Errorf("invite cannot be revoked in the %v status", invite.Status)
+	}
+	return invite.Address, nil
+}
+
+func (m *Manager) view(saved *types.SavedInvite) *Invite {
+	s := m.appState.State
+	address := saved.Receiver
+	if saved.ActivationTxHash != (common.Hash{}) {
+		address = saved.ActivatedAddress
+	}
+	identityState := s.GetIdentityState(address)
+	invite := &Invite{
+		SavedInvite: saved,
+		Address:     address,
+		State:       identityState,
+	}
+	switch identityState {
+	case state.Candidate:
+		invite.Status = Activated
+	case state.Newbie, state.Verified, state.Suspended, state.Zombie:
+		invite.Status = Validated
+	}
+	if saved.KillTxHash != (common.Hash{}) {
+		invite.Status = Revoked
+	}
+	if invite.Status != "" {
+		return invite
+	}
+	switch {
+	case identityState == state.Invite:
+		invite.Status = Issued
+	case saved.MinedHeight == 0 && saved.Epoch == s.Epoch():
+		invite.Status = Pending
+	case saved.ActivationTxHash == (common.Hash{}):
+		invite.Status = Expired
+	default:
+		invite.Status = Killed
+	}
+	if invite.Status == Issued || invite.Status == Pending {
+		invite.ExpiresAt = s.NextValidationTime()
+		invite.ExpiringSoon = time.Until(invite.ExpiresAt) < ExpirationWarningPeriod
+	}
+	return invite
+}
+
+func (m *Manager) handleBlock(block *types.Block) {
+	if block.IsEmpty() {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, tx := range block.Body.Transactions {
+		if tx.To == nil {
+			continue
+		}
+		switch tx.Type {
+		case types.InviteTx:
+			if invite := m.repo.ReadInvite(*tx.To); invite != nil && invite.TxHash == tx.Hash() {
+				invite.MinedHeight = block.Height()
+				m.repo.WriteInvite(invite)
+			}
+		case types.ActivationTx:
+			sender, _ := types.Sender(tx)
+			if invite := m.repo.ReadInvite(sender); invite != nil && invite.ActivationTxHash == (common.Hash{}) {
+				invite.ActivatedAddress = *tx.To
+				invite.ActivationTxHash = tx.Hash()
+				m.repo.WriteInvite(invite)
+				m.log.Info("Invite activated", "receiver", sender.Hex(), "address", tx.To.Hex())
+			}
+		case types.KillInviteeTx:
+			sender, _ := types.Sender(tx)
+			if invite := m.findByAddress(sender, *tx.To); invite != nil {
+				invite.KillTxHash = tx.Hash()
+				m.repo.WriteInvite(invite)
+			}
+		}
+	}
+}
+
+func (m *Manager) findByAddress(inviter common.Address, address common.Address) *types.SavedInvite {
+	if invite := m.repo.ReadInvite(address); invite != nil && invite.Inviter == inviter && invite.ActivationTxHash == (common.Hash{}) {
+		return invite
+	}
+	for _, invite := range m.repo.GetInvites() {
+		if invite.Inviter == inviter && invite.ActivationTxHash != (common.Hash{}) && invite.ActivatedAddress == address {
+			return invite
+		}
+	}
+	return nil
+}
+
+func (m *Manager) encryptionKey() (*ecies.PrivateKey, error) {
+	sig, err := m.secStore.SignSeed([]byte(encryptionKeySeed))
+	if err != nil {
+		return nil, err
+	}
+	// the key is derived from the hash of the deterministic signature, so the same key is restored on every call
+	key, err := crypto.ToECDSA(crypto.Keccak256(sig))
+	if err != nil {
+		return nil, err
+	}
+	return ecies.ImportECDSA(key), nil
+}
@@ -0,0 +1,153 @@
+package invites
+
+import (
+	"crypto/ecdsa"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/core/state"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/secstore"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tm-db"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func getManager() (*Manager, *ecdsa.PrivateKey) {
+	bus := eventbus.New()
+	appState := appstate.NewAppState(db.NewMemDB(), bus)
+	appState.State.SetNextValidationTime(time.Now().Add(ExpirationWarningPeriod * 2))
+	appState.Commit(nil)
+	appState.Initialize(0)
+
+	key, _ := crypto.GenerateKey()
+	secStore := secstore.NewSecStore()
+	secStore.AddKey(crypto.FromECDSA(key))
+
+	return NewManager(db.NewMemDB(), appState, secStore, bus), key
+}
+
+func getBlock(height uint64, txs ...*types.Transaction) *types.Block {
+	return &types.Block{
+		Header: &types.Header{
+			ProposedHeader: &types.ProposedHeader{
+				Height: height,
+			},
+		},
+		Body: &types.Body{
+			Transactions: txs,
+		},
+	}
+}
+
+func TestManager_Key(t *testing.T) {
+	r := require.New(t)
+	m, _ := getManager()
+
+	receiverKey, _ := crypto.GenerateKey()
+	receiver := crypto.PubkeyToAddress(receiverKey.PublicKey)
+	r.NoError(m.Add(receiver, common.Hash{0x1}, big.NewInt(1), receiverKey))
+
+	saved := m.repo.ReadInvite(receiver)
+	r.NotEmpty(saved.EncryptedKey)
+	r.NotContains(string(saved.EncryptedKey), string(crypto.FromECDSA(receiverKey)))
+
+	key, err := m.Key(receiver)
+	r.NoError(err)
+	r.Equal(crypto.FromECDSA(receiverKey), crypto.FromECDSA(key))
+
+	given := common.Address{0x2}
+	r.NoError(m.Add(given, common.Hash{0x2}, big.NewInt(1), nil))
+	_, err = m.Key(given)
+	r.Error(err)
+
+	_, err = m.Key(common.Address{0x3})
+	r.Error(err)
+}
+
+func TestManager_Status(t *testing.T) {
+	r := require.New(t)
+	m, key := getManager()
+	s := m.appState.State
+
+	receiverKey, _ := crypto.GenerateKey()
+	receiver := crypto.PubkeyToAddress(receiverKey.PublicKey)
+	inviteTx, _ := types.SignTx(&types.Transaction{Type: types.InviteTx, To: &receiver}, key)
+	r.NoError(m.Add(receiver, inviteTx.Hash(), big.NewInt(1), receiverKey))
+
+	status := func(receiver common.Address) *Invite {
+		invite, err := m.Invite(receiver)
+		r.NoError(err)
+		return invite
+	}
+
+	invite := status(receiver)
+	r.Equal(Pending, invite.Status)
+	r.False(invite.ExpiringSoon)
+
+	m.handleBlock(getBlock(2, inviteTx))
+	s.SetState(receiver, state.Invite)
+	invite = status(receiver)
+	r.Equal(Issued, invite.Status)
+	r.Equal(uint64(2), invite.MinedHeight)
+
+	s.SetNextValidationTime(time.Now().Add(ExpirationWarningPeriod / 2))
+	r.True(status(receiver).ExpiringSoon)
+
+	address := common.Address{0x4}
+	activationTx, _ := types.SignTx(&types.Transaction{Type: types.ActivationTx, To: &address}, receiverKey)
+	m.handleBlock(getBlock(3, activationTx))
+	s.SetState(receiver, state.Killed)
+	s.SetState(address, state.Candidate)
+	invite = status(receiver)
+	r.Equal(Activated, invite.Status)
+	r.Equal(address, invite.Address)
+	r.Equal(activationTx.Hash(), invite.ActivationTxHash)
+	r.Equal([]*Invite{invite}, m.Invites())
+
+	s.SetState(address, state.Newbie)
+	r.Equal(Validated, status(receiver).Status)
+
+	s.SetState(address, state.Killed)
+	r.Equal(Killed, status(receiver).Status)
+
+	s.SetState(address, state.Candidate)
+	address, err := m.RevocableAddress(receiver)
+	r.NoError(err)
+	r.Equal(common.Address{0x4}, address)
+
+	killTx, _ := types.SignTx(&types.Transaction{Type: types.KillInviteeTx, To: &address}, key)
+	m.handleBlock(getBlock(4, killTx))
+	r.Equal(Revoked, status(receiver).Status)
+	_, err = m.RevocableAddress(receiver)
+	r.Error(err)
+}
+
+func TestManager_Expiration(t *testing.T) {
+	r := require.New(t)
+	m, key := getManager()
+	s := m.appState.State
+
+	receiver := common.Address{0x1}
+	inviteTx, _ := types.SignTx(&types.Transaction{Type: types.InviteTx, To: &receiver}, key)
+	r.NoError(m.Add(receiver, inviteTx.Hash(), big.NewInt(1), nil))
+	m.handleBlock(getBlock(2, inviteTx))
+	s.SetState(receiver, state.Invite)
+	r.Equal(Issued, m.Invites()[0].Status)
+
+	// the invite is removed from the state at the end of the epoch if it is not activated
+	s.SetState(receiver, state.Undefined)
+	s.IncEpoch()
+	invite := m.Invites()[0]
+	r.Equal(Expired, invite.Status)
+	r.True(invite.ExpiresAt.IsZero())
+
+	pending := common.Address{0x2}
+	r.NoError(m.Add(pending, common.Hash{0x2}, big.NewInt(1), nil))
+	invite, err := m.Invite(pending)
+	r.NoError(err)
+	r.Equal(Pending, invite.Status)
+}
@@ -0,0 +1,116 @@
+package mempool
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/events"
+	"time"
+)
+
+const (
+	DropReasonExpired      = "tx lifetime is expired"
+	DropReasonStaleEpoch   = "tx epoch is stale"
+	DropReasonUsedNonce    = "tx nonce is already used"
+	DropReasonInvalidChain = "tx or its predecessor became invalid"
+	DropReasonEvicted      = "tx is evicted by a better paying one"
+
+	maxDroppedOwnTxs     = 100
+	expirationCheckDelay = time.Minute
+)
+
+type DroppedTx struct {
+	Tx     *types.Transaction
+	Reason string
+	Time   time.Time
+}
+
+// GetDroppedOwnTxs returns recently dropped txs of the coinbase which have not been mined
+func (txpool *TxPool) GetDroppedOwnTxs() []*DroppedTx {
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+	res := make([]*DroppedTx, len(txpool.droppedOwnTxs))
+	copy(res, txpool.droppedOwnTxs)
+	return res
+}
+
+// drop removes the tx which is not going to be mined and notifies subscribers about it
+func (txpool *TxPool) drop(tx *types.Transaction, reason string) {
+	txpool.remove(tx)
+	sender, _ := types.Sender(tx)
+	own := sender == txpool.coinbase
+	if own {
+		txpool.log.Warn("Own tx dropped", "hash", tx.Hash().Hex(), "reason", reason)
+		txpool.droppedOwnTxs = append(txpool.droppedOwnTxs, &DroppedTx{
+			Tx:     tx,
+			Reason: reason,
+			Time:   time.Now(),
+		})
+		if len(txpool.droppedOwnTxs) > maxDroppedOwnTxs {
+			txpool.droppedOwnTxs[0] = nil
+			txpool.droppedOwnTxs = txpool.droppedOwnTxs[1:]
+		}
+	} else {
+		txpool.log.Debug("Tx dropped", "hash", tx.Hash().Hex(), "reason", reason)
+	}
+	txpool.bus.Publish(&events.TxDroppedEvent{
+		Tx:     tx,
+		Reason: reason,
+		Own:    own,
+	})
+}
+
+func (txpool *TxPool) dropLocked(tx *types.Transaction, reason string) {
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+	txpool.drop(tx, reason)
+}
+
+// purgeStaleEpoch drops pending and deferred txs of past epochs right after the epoch change
+func (txpool *TxPool) purgeStaleEpoch(globalEpoch uint16) {
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+	for _, tx := range txpool.allTxs() {
+		if tx.Epoch < globalEpoch {
+			txpool.drop(tx, DropReasonStaleEpoch)
+		}
+	}
+	var deferredTxs []*types.Transaction
+	for _, tx := range txpool.deferredTxs {
+		if tx.Epoch < globalEpoch {
+			txpool.knownDeferredTxs.Remove(tx.Hash())
+			txpool.drop(tx, DropReasonStaleEpoch)
+			continue
+		}
+		deferredTxs = append(deferredTxs, tx)
+	}
+	txpool.deferredTxs = deferredTxs
+}
+
+func (txpool *TxPool) expirationLoop() {
+	ticker := time.NewTicker(expirationCheckDelay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			txpool.purgeExpired()
+		case <-txpool.quit:
+			return
+		}
+	}
+}
+
+// purgeExpired drops txs which have not been mined during the configured lifetime,
+// ceremony txs are never expired, they are dropped only when the epoch changes
+func (txpool *TxPool) purgeExpired() {
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+	deadline := time.Now().Add(-txpool.cfg.TxLifetime)
+	for _, tx := range txpool.allTxs() {
+		if priorityTypes[tx.Type] {
+			continue
+		}
+		if arrival, ok := txpool.arrivals[tx.Hash()]; ok && arrival.Before(deadline) {
+			txpool.drop(tx, DropReasonExpired)
+		}
+	}
+	txpool.reorganize()
+}
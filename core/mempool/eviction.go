@@ -0,0 +1,103 @@
+package mempool
+
+import (
+	"container/heap"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+)
+
+// evictionItem is the last tx of a sender, only it can be evicted without making a nonce gap
+type evictionItem struct {
+	sender   common.Address
+	tx       *types.Transaction
+	queueLen int
+	index    int
+}
+
+// evictionQueue keeps evictable last txs of senders ordered by fee per byte, the cheapest is the first.
+// Txs with equal fee per byte are ordered by the length of the sender's queue, the longest is the first.
+type evictionQueue struct {
+	items    []*evictionItem
+	bySender map[common.Address]*evictionItem
+}
+
+func newEvictionQueue() *evictionQueue {
+	return &evictionQueue{
+		bySender: make(map[common.Address]*evictionItem),
+	}
+}
+
+func (q *evictionQueue) Len() int {
+	return len(q.items)
+}
+
+func (q *evictionQueue) Less(i, j int) bool {
+	cmp := compareFeePerByte(q.items[i].tx, q.items[j].tx)
+	return cmp < 0 || cmp == 0 && q.items[i].queueLen > q.items[j].queueLen
+}
+
+func (q *evictionQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *evictionQueue) Push(x interface{}) {
+	item := x.(*evictionItem)
+	item.index = len(q.items)
+	q.items = append(q.items, item)
+}
+
+func (q *evictionQueue) Pop() interface{} {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items[n-1] = nil
+	q.items = q.items[:n-1]
+	return item
+}
+
+// update sets the last tx of the sender, the sender is removed from the queue if it has no txs
+// or its last tx is a ceremony one since ceremony txs are never evicted
+func (q *evictionQueue) update(sender common.Address, last *types.Transaction, queueLen int) {
+	item, ok := q.bySender[sender]
+	if last == nil || priorityTypes[last.Type] {
+		if ok {
+			heap.Remove(q, item.index)
+			delete(q.bySender, sender)
+		}
+		return
+	}
+	if ok {
+		item.tx, item.queueLen = last, queueLen
+		heap.Fix(q, item.index)
+		return
+	}
+	item = &evictionItem{
+		sender:   sender,
+		tx:       last,
+		queueLen: queueLen,
+	}
+	q.bySender[sender] = item
+	heap.Push(q, item)
+}
+
+// cheapest returns the cheapest evictable tx which does not belong to the excluded sender
+func (q *evictionQueue) cheapest(excluded common.Address) *types.Transaction {
+	if len(q.items) == 0 {
+		return nil
+	}
+	if q.items[0].sender != excluded {
+		return q.items[0].tx
+	}
+	// the next cheapest item is one of the root children
+	next := -1
+	for _, i := range []int{1, 2} {
+		if i < len(q.items) && (next < 0 || q.Less(i, next)) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return nil
+	}
+	return q.items[next].tx
+}
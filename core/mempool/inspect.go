@@ -0,0 +1,77 @@
+package mempool
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"time"
+)
+
+type buildingResult struct {
+	time     time.Time
+	included map[common.Hash]struct{}
+	skipped  map[common.Hash]string
+}
+
+type TxInfo struct {
+	Tx         *types.Transaction
+	Arrival    time.Time
+	Executable bool
+	Deferred   bool
+	// SkipReason explains why the tx was not included in the last block proposed by the node
+	SkipReason string
+}
+
+// Inspect returns pending, queued and deferred txs of the address or of all senders if the address is nil
+func (txpool *TxPool) Inspect(address *common.Address) []*TxInfo {
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+
+	matches := func(tx *types.Transaction) bool {
+		if address == nil {
+			return true
+		}
+		sender, _ := types.Sender(tx)
+		return sender == *address
+	}
+
+	var list []*TxInfo
+	for _, tx := range txpool.pending {
+		if matches(tx) {
+			list = append(list, txpool.txInfo(tx, true, false))
+		}
+	}
+	for _, tx := range txpool.queued {
+		if matches(tx) {
+			list = append(list, txpool.txInfo(tx, false, false))
+		}
+	}
+	for _, tx := range txpool.deferredTxs {
+		if matches(tx) {
+			list = append(list, txpool.txInfo(tx, false, true))
+		}
+	}
+	return list
+}
+
+func (txpool *TxPool) txInfo(tx *types.Transaction, executable bool, deferred bool) *TxInfo {
+	hash := tx.Hash()
+	info := &TxInfo{
+		Tx:         tx,
+		Arrival:    txpool.arrivals[hash],
+		Executable: executable,
+		Deferred:   deferred,
+	}
+	switch {
+	case deferred:
+		info.SkipReason = SkipReasonNodeSyncing
+	case !executable:
+		info.SkipReason = SkipReasonQueued
+	case txpool.lastBuild == nil || txpool.lastBuild.time.Before(info.Arrival):
+		info.SkipReason = SkipReasonNotBuilt
+	default:
+		if _, ok := txpool.lastBuild.included[hash]; !ok {
+			info.SkipReason = txpool.lastBuild.skipped[hash]
+		}
+	}
+	return info
+}
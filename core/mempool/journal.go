@@ -0,0 +1,105 @@
+package mempool
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/log"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+)
+
+var errNoActiveJournal = errors.New("no active journal")
+
+// txJournal is a rotating log of transactions accepted by the pool,
+// it allows to restore not mined transactions after the node restart
+type txJournal struct {
+	path   string
+	writer io.WriteCloser
+}
+
+func newTxJournal(path string) *txJournal {
+	return &txJournal{
+		path: path,
+	}
+}
+
+// load parses the journal and passes every transaction to the add callback,
+// transactions rejected by the callback are just counted as dropped
+func (journal *txJournal) load(add func(tx *types.Transaction) error) error {
+	if _, err := os.Stat(journal.path); os.IsNotExist(err) {
+		return nil
+	}
+	input, err := os.Open(journal.path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	total, dropped := 0, 0
+	for {
+		tx := new(types.Transaction)
+		if err = stream.Decode(tx); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			break
+		}
+		total++
+		if err := add(tx); err != nil {
+			log.Debug("Failed to add journaled tx", "hash", tx.Hash().Hex(), "err", err)
+			dropped++
+		}
+	}
+	log.Info("Loaded local tx journal", "txs", total, "dropped", dropped)
+	return err
+}
+
+func (journal *txJournal) insert(tx *types.Transaction) error {
+	if journal.writer == nil {
+		return errNoActiveJournal
+	}
+	return rlp.Encode(journal.writer, tx)
+}
+
+// rotate regenerates the journal from the given transactions and reopens it for appending
+func (journal *txJournal) rotate(txs []*types.Transaction) error {
+	if journal.writer != nil {
+		if err := journal.writer.Close(); err != nil {
+			return err
+		}
+		journal.writer = nil
+	}
+	replacement, err := os.OpenFile(journal.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err = rlp.Encode(replacement, tx); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	replacement.Close()
+
+	if err = os.Rename(journal.path+".new", journal.path); err != nil {
+		return err
+	}
+	sink, err := os.OpenFile(journal.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	journal.writer = sink
+	log.Debug("Regenerated local tx journal", "txs", len(txs))
+	return nil
+}
+
+func (journal *txJournal) close() error {
+	var err error
+	if journal.writer != nil {
+		err = journal.writer.Close()
+		journal.writer = nil
+	}
+	return err
+}
@@ -0,0 +1,140 @@
+package mempool
+
+import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/appstate"
+	"sync"
+)
+
+type nonceKey struct {
+	addr  common.Address
+	epoch uint16
+}
+
+type accountNonces struct {
+	// outstanding nonces are reserved but the txs have not reached the pool yet
+	outstanding map[uint32]struct{}
+	// released nonces were reserved but the txs have been rejected, they are reused to avoid gaps
+	released map[uint32]struct{}
+}
+
+// nonceAllocator hands out nonces for txs signed by the node, so concurrent senders never get the same nonce
+type nonceAllocator struct {
+	appState *appstate.AppState
+	mutex    sync.Mutex
+	accounts map[nonceKey]*accountNonces
+}
+
+func newNonceAllocator(appState *appstate.AppState) *nonceAllocator {
+	return &nonceAllocator{
+		appState: appState,
+		accounts: make(map[nonceKey]*accountNonces),
+	}
+}
+
+func (a *nonceAllocator) account(addr common.Address, epoch uint16) *accountNonces {
+	key := nonceKey{addr, epoch}
+	acc, ok := a.accounts[key]
+	if !ok {
+		acc = &accountNonces{
+			outstanding: make(map[uint32]struct{}),
+			released:    make(map[uint32]struct{}),
+		}
+		a.accounts[key] = acc
+	}
+	return acc
+}
+
+// ReserveNonce returns the next free nonce of the account, the reservation should be completed
+// by ConfirmNonce or ReleaseNonce when the tx is accepted or rejected by the pool
+func (txpool *TxPool) ReserveNonce(addr common.Address, epoch uint16) uint32 {
+	a := txpool.nonces
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	// the pool lock protects the nonce cache which is replaced by ResetTo, it is taken once per reservation
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+
+	acc := a.account(addr, epoch)
+	next := a.nextNonce(addr, epoch, acc)
+
+	stateNonce := uint32(0)
+	if a.appState.State.GetEpoch(addr) == epoch {
+		stateNonce = a.appState.State.GetNonce(addr)
+	}
+	nonce := next
+	for released := range acc.released {
+		if released <= stateNonce || released >= next || txpool.hasNonceLocked(addr, epoch, released) {
+			delete(acc.released, released)
+			continue
+		}
+		if released < nonce {
+			nonce = released
+		}
+	}
+	delete(acc.released, nonce)
+	acc.outstanding[nonce] = struct{}{}
+	return nonce
+}
+
+// ReserveNonces reserves the given number of consecutive nonces and returns the first one
+func (txpool *TxPool) ReserveNonces(addr common.Address, epoch uint16, count int) uint32 {
+	a := txpool.nonces
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+
+	acc := a.account(addr, epoch)
+	first := a.nextNonce(addr, epoch, acc)
+	for i := 0; i < count; i++ {
+		acc.outstanding[first+uint32(i)] = struct{}{}
+	}
+	return first
+}
+
+// nextNonce should be called under the pool lock
+func (a *nonceAllocator) nextNonce(addr common.Address, epoch uint16, acc *accountNonces) uint32 {
+	next := a.appState.NonceCache.GetNonce(addr, epoch)
+	for nonce := range acc.outstanding {
+		if nonce > next {
+			next = nonce
+		}
+	}
+	return next + 1
+}
+
+// ConfirmNonce completes the reservation when the tx has been accepted
+func (txpool *TxPool) ConfirmNonce(addr common.Address, epoch uint16, nonce uint32) {
+	a := txpool.nonces
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	acc := a.account(addr, epoch)
+	delete(acc.outstanding, nonce)
+	if len(acc.outstanding) == 0 && len(acc.released) == 0 {
+		delete(a.accounts, nonceKey{addr, epoch})
+	}
+}
+
+// ReleaseNonce returns the reserved nonce back when the tx has been rejected
+func (txpool *TxPool) ReleaseNonce(addr common.Address, epoch uint16, nonce uint32) {
+	a := txpool.nonces
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	acc := a.account(addr, epoch)
+	if _, ok := acc.outstanding[nonce]; !ok {
+		return
+	}
+	delete(acc.outstanding, nonce)
+	acc.released[nonce] = struct{}{}
+}
+
+// hasNonceLocked should be called under the pool lock
+func (txpool *TxPool) hasNonceLocked(addr common.Address, epoch uint16, nonce uint32) bool {
+	for _, tx := range txpool.senderTxs(addr) {
+		if tx.Epoch == epoch && tx.AccountNonce == nonce {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package mempool
+
+import (
+	"github.com/idena-network/idena-go/blockchain/fee"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/events"
+	"github.com/idena-network/idena-go/log"
+	"github.com/idena-network/idena-go/secstore"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	OwnTxPending  = "pending"
+	OwnTxMined    = "mined"
+	OwnTxReplaced = "replaced"
+	OwnTxDropped  = "dropped"
+
+	maxFinishedOwnTxs = 200
+)
+
+type OwnTx struct {
+	Tx            *types.Transaction
+	State         string
+	Submitted     time.Time
+	Broadcasts    int
+	LastBroadcast time.Time
+	BlockHash     common.Hash
+	BlockHeight   uint64
+	ReplacedBy    *common.Hash
+	DropReason    string
+}
+
+// OwnTxWatcher tracks txs of the coinbase until they are mined, rebroadcasts them periodically
+// and optionally re-signs them with a higher max fee if the network fee exceeds their cap
+type OwnTxWatcher struct {
+	cfg      *config.MempoolConfig
+	bus      eventbus.Bus
+	txpool   *TxPool
+	appState *appstate.AppState
+	secStore *secstore.SecStore
+	log      log.Logger
+	mutex    sync.Mutex
+	txs      map[common.Hash]*OwnTx
+	finished []common.Hash
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewOwnTxWatcher(cfg *config.MempoolConfig, bus eventbus.Bus, txpool *TxPool, appState *appstate.AppState, secStore *secstore.SecStore) *OwnTxWatcher {
+	return &OwnTxWatcher{
+		cfg:      cfg,
+		bus:      bus,
+		txpool:   txpool,
+		appState: appState,
+		secStore: secStore,
+		log:      log.New("component", "ownTxWatcher"),
+		txs:      make(map[common.Hash]*OwnTx),
+		quit:     make(chan struct{}),
+	}
+}
+
+func (w *OwnTxWatcher) Start() {
+	_ = w.bus.Subscribe(events.NewTxEventID, func(e eventbus.Event) {
+		newTxEvent := e.(*events.NewTxEvent)
+		if newTxEvent.Own {
+			w.track(newTxEvent.Tx)
+		}
+	})
+	_ = w.bus.Subscribe(events.AddBlockEventID, func(e eventbus.Event) {
+		newBlockEvent := e.(*events.NewBlockEvent)
+		w.handleBlock(newBlockEvent.Block)
+	})
+	_ = w.bus.Subscribe(events.TxReplacedEventID, func(e eventbus.Event) {
+		replacedEvent := e.(*events.TxReplacedEvent)
+		newHash := replacedEvent.New.Hash()
+		w.finish(replacedEvent.Old.Hash(), func(ownTx *OwnTx) {
+			ownTx.State = OwnTxReplaced
+			ownTx.ReplacedBy = &newHash
+		})
+	})
+	_ = w.bus.Subscribe(events.TxDroppedEventID, func(e eventbus.Event) {
+		droppedEvent := e.(*events.TxDroppedEvent)
+		if droppedEvent.Own {
+			w.finish(droppedEvent.Tx.Hash(), func(ownTx *OwnTx) {
+				ownTx.State = OwnTxDropped
+				ownTx.DropReason = droppedEvent.Reason
+			})
+		}
+	})
+	if w.cfg.RebroadcastInterval > 0 {
+		go w.loop()
+	}
+}
+
+// GetOwnTxs returns tracked txs, the newest are first
+func (w *OwnTxWatcher) GetOwnTxs() []OwnTx {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	list := make([]OwnTx, 0, len(w.txs))
+	for _, ownTx := range w.txs {
+		list = append(list, *ownTx)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Submitted.After(list[j].Submitted)
+	})
+	return list
+}
+
+func (w *OwnTxWatcher) track(tx *types.Transaction) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	now := time.Now()
+	if ownTx, ok := w.txs[tx.Hash()]; ok {
+		ownTx.Broadcasts++
+		ownTx.LastBroadcast = now
+		return
+	}
+	w.txs[tx.Hash()] = &OwnTx{
+		Tx:            tx,
+		State:         OwnTxPending,
+		Submitted:     now,
+		Broadcasts:    1,
+		LastBroadcast: now,
+	}
+}
+
+func (w *OwnTxWatcher) handleBlock(block *types.Block) {
+	if block.IsEmpty() {
+		return
+	}
+	for _, tx := range block.Body.Transactions {
+		w.finish(tx.Hash(), func(ownTx *OwnTx) {
+			ownTx.State = OwnTxMined
+			ownTx.BlockHash = block.Hash()
+			ownTx.BlockHeight = block.Height()
+		})
+	}
+}
+
+func (w *OwnTxWatcher) finish(hash common.Hash, update func(ownTx *OwnTx)) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	ownTx, ok := w.txs[hash]
+	if !ok || ownTx.State != OwnTxPending {
+		return
+	}
+	update(ownTx)
+	w.finished = append(w.finished, hash)
+	if len(w.finished) > maxFinishedOwnTxs {
+		delete(w.txs, w.finished[0])
+		w.finished = w.finished[1:]
+	}
+}
+
+func (w *OwnTxWatcher) loop() {
+	ticker := time.NewTicker(w.cfg.RebroadcastInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.rebroadcast()
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// Stop terminates rebroadcasting, tracked txs are still updated by events
+func (w *OwnTxWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.quit)
+	})
+}
+
+func (w *OwnTxWatcher) pendingTxs() []*types.Transaction {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var list []*types.Transaction
+	for _, ownTx := range w.txs {
+		if ownTx.State == OwnTxPending {
+			list = append(list, ownTx.Tx)
+		}
+	}
+	return list
+}
+
+func (w *OwnTxWatcher) rebroadcast() {
+	for _, tx := range w.pendingTxs() {
+		if w.txpool.GetTx(tx.Hash()) == nil {
+			continue
+		}
+		if w.cfg.ResignOwnTxs {
+			if resigned := w.resign(tx); resigned {
+				continue
+			}
+		}
+		// peers which have already seen the tx are filtered out by ProtocolManager
+		w.bus.Publish(&events.NewTxEvent{
+			Tx:  tx,
+			Own: true,
+		})
+	}
+}
+
+// resign replaces the tx with a copy having a higher max fee if the current fee exceeds the tx cap
+func (w *OwnTxWatcher) resign(tx *types.Transaction) bool {
+	if priorityTypes[tx.Type] || tx.Type == types.SubmitFlipTx {
+		return false
+	}
+	currentFee := fee.CalculateFee(w.appState.ValidatorsCache.NetworkSize(), w.appState.State.FeePerByte(), tx)
+	if currentFee.Sign() == 0 || currentFee.Cmp(tx.MaxFeeOrZero()) <= 0 {
+		return false
+	}
+	maxFee := new(big.Int).Mul(currentFee, big.NewInt(2))
+	if bumped := bumpPrice(tx.MaxFeeOrZero(), w.cfg.TxPriceBump); maxFee.Cmp(bumped) < 0 {
+		maxFee = bumped
+	}
+	newTx := &types.Transaction{
+		AccountNonce: tx.AccountNonce,
+		Type:         tx.Type,
+		To:           tx.To,
+		Amount:       tx.Amount,
+		MaxFee:       maxFee,
+		Tips:         bumpPrice(tx.TipsOrZero(), w.cfg.TxPriceBump),
+		Payload:      tx.Payload,
+		Epoch:        tx.Epoch,
+	}
+	signedTx, err := w.secStore.SignTx(newTx)
+	if err != nil {
+		w.log.Warn("Failed to re-sign own tx", "hash", tx.Hash().Hex(), "err", err)
+		return false
+	}
+	if err := w.txpool.Add(signedTx); err != nil {
+		w.log.Warn("Failed to replace own tx", "hash", tx.Hash().Hex(), "err", err)
+		return false
+	}
+	w.log.Info("Own tx re-signed with higher max fee", "old", tx.Hash().Hex(), "new", signedTx.Hash().Hex(),
+		"maxFee", maxFee)
+	return true
+}
+
+// bumpPrice returns the price increased by the given percentage, rounded up
+func bumpPrice(price *big.Int, percent int) *big.Int {
+	res := new(big.Int).Mul(price, big.NewInt(int64(100+percent)))
+	res.Add(res, big.NewInt(99))
+	return res.Div(res, big.NewInt(100))
+}
@@ -7,6 +7,15 @@ import (
 	"github.com/idena-network/idena-go/core/appstate"
 )
 
+const (
+	SkipReasonLowFee      = "max fee is lower than the current fee"
+	SkipReasonNonce       = "previous nonce is not included"
+	SkipReasonBlockSize   = "block size limit is reached"
+	SkipReasonQueued      = "tx is queued"
+	SkipReasonNotBuilt    = "tx arrived after the last proposed block"
+	SkipReasonNodeSyncing = "node is syncing"
+)
+
 type buildingContext struct {
 	appState           *appstate.AppState
 	sortedTxs          []*types.Transaction
@@ -15,6 +24,7 @@ type buildingContext struct {
 	curNoncesPerSender map[common.Address]uint32
 	blockTxs           []*types.Transaction
 	blockSize          int
+	skipped            map[common.Hash]string
 }
 
 func newBuildingContext(
@@ -31,6 +41,7 @@ func newBuildingContext(
 		sortedPriorityTxs:  sortedPriorityTxs,
 		sortedTxsPerSender: sortedTxsPerSender,
 		curNoncesPerSender: curNoncesPerSender,
+		skipped:            make(map[common.Hash]string),
 	}
 	return ctx
 }
@@ -82,15 +93,26 @@ func (ctx *buildingContext) addNextPriorityTxToBlock() {
 
 func (ctx *buildingContext) addTxsToBlock() {
 	txs := ctx.sortedTxs
-	for _, tx := range txs {
+	for i, tx := range txs {
+		sender, _ := types.Sender(tx)
+		if ctx.curNoncesPerSender[sender] >= tx.AccountNonce {
+			// added with a priority tx
+			continue
+		}
 		if !ctx.checkFee(tx) {
+			ctx.skipped[tx.Hash()] = SkipReasonLowFee
 			continue
 		}
-		sender, _ := types.Sender(tx)
 		if ctx.curNoncesPerSender[sender]+1 != tx.AccountNonce {
+			ctx.skipped[tx.Hash()] = SkipReasonNonce
 			continue
 		}
 		if ctx.blockSize+tx.Size() > BlockBodySize {
+			for _, skippedTx := range txs[i:] {
+				if _, ok := ctx.skipped[skippedTx.Hash()]; !ok {
+					ctx.skipped[skippedTx.Hash()] = SkipReasonBlockSize
+				}
+			}
 			return
 		}
 		ctx.blockTxs = append(ctx.blockTxs, tx)
@@ -6,6 +6,7 @@ import (
 	"github.com/idena-network/idena-go/blockchain/validation"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
 	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/core/state"
 	"github.com/idena-network/idena-go/events"
@@ -14,6 +15,7 @@ import (
 	"math/big"
 	"sort"
 	"sync"
+	"time"
 )
 
 const (
@@ -22,7 +24,8 @@ const (
 )
 
 var (
-	DuplicateTxError = errors.New("tx with same hash already exists")
+	DuplicateTxError       = errors.New("tx with same hash already exists")
+	ReplacementUnderpriced = errors.New("replacement tx underpriced")
 
 	priorityTypes = map[types.TxType]bool{
 		types.SubmitAnswersHashTx:  true,
@@ -42,6 +45,12 @@ type TxPool struct {
 	deferredTxs      []*types.Transaction
 	pending          map[common.Hash]*types.Transaction
 	pendingPerAddr   map[common.Address]map[common.Hash]*types.Transaction
+	queued           map[common.Hash]*types.Transaction
+	queuedPerAddr    map[common.Address]map[common.Hash]*types.Transaction
+	arrivals         map[common.Hash]time.Time
+	lastBuild        *buildingResult
+	droppedOwnTxs    []*DroppedTx
+	epoch            uint16
 	totalTxLimit     int
 	addrTxLimit      int
 	txSubscription   chan *types.Transaction
@@ -54,20 +63,33 @@ type TxPool struct {
 	coinbase         common.Address
 	minFeePerByte    *big.Int
 	tmpNonceCache    *state.NonceCache
+	cfg              *config.MempoolConfig
+	journal          *txJournal
+	nonces           *nonceAllocator
+	evictionQueue    *evictionQueue
+	quit             chan struct{}
+	stopOnce         sync.Once
 }
 
-func NewTxPool(appState *appstate.AppState, bus eventbus.Bus, totalTxLimit int, addrTxLimit int, minFeePerByte *big.Int) *TxPool {
+func NewTxPool(appState *appstate.AppState, bus eventbus.Bus, cfg *config.MempoolConfig, minFeePerByte *big.Int) *TxPool {
 	pool := &TxPool{
 		pending:          make(map[common.Hash]*types.Transaction),
 		pendingPerAddr:   make(map[common.Address]map[common.Hash]*types.Transaction),
+		queued:           make(map[common.Hash]*types.Transaction),
+		queuedPerAddr:    make(map[common.Address]map[common.Hash]*types.Transaction),
+		arrivals:         make(map[common.Hash]time.Time),
 		knownDeferredTxs: mapset.NewSet(),
-		totalTxLimit:     totalTxLimit,
-		addrTxLimit:      addrTxLimit,
+		totalTxLimit:     cfg.TotalTxLimit,
+		addrTxLimit:      cfg.AddrTxLimit,
 		mutex:            &sync.Mutex{},
 		appState:         appState,
 		log:              log.New(),
 		bus:              bus,
 		minFeePerByte:    minFeePerByte,
+		cfg:              cfg,
+		nonces:           newNonceAllocator(appState),
+		evictionQueue:    newEvictionQueue(),
+		quit:             make(chan struct{}),
 	}
 
 	_ = pool.bus.Subscribe(events.AddBlockEventID,
@@ -78,9 +100,87 @@ func NewTxPool(appState *appstate.AppState, bus eventbus.Bus, totalTxLimit int,
 	return pool
 }
 
-func (txpool *TxPool) Initialize(head *types.Header, coinbase common.Address) {
+func (txpool *TxPool) Initialize(head *types.Header, coinbase common.Address, journalPath string) {
 	txpool.head = head
 	txpool.coinbase = coinbase
+	txpool.epoch = txpool.appState.State.Epoch()
+
+	if journalPath != "" {
+		txpool.loadJournal(journalPath)
+	}
+	if txpool.cfg.TxLifetime > 0 {
+		go txpool.expirationLoop()
+	}
+}
+
+func (txpool *TxPool) loadJournal(path string) {
+	journal := newTxJournal(path)
+	if err := journal.load(txpool.Add); err != nil {
+		txpool.log.Warn("Failed to load tx journal", "err", err)
+	}
+	txpool.mutex.Lock()
+	txpool.journal = journal
+	txpool.mutex.Unlock()
+	txpool.rotateJournal()
+	if txpool.cfg.RejournalInterval > 0 {
+		go txpool.journalLoop()
+	}
+}
+
+func (txpool *TxPool) journalLoop() {
+	ticker := time.NewTicker(txpool.cfg.RejournalInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			txpool.rotateJournal()
+		case <-txpool.quit:
+			return
+		}
+	}
+}
+
+// Stop rotates the journal for the last time and closes it, txs added after that are not journaled
+func (txpool *TxPool) Stop() {
+	txpool.stopOnce.Do(func() {
+		close(txpool.quit)
+		txpool.mutex.Lock()
+		defer txpool.mutex.Unlock()
+		if txpool.journal == nil {
+			return
+		}
+		txs := append(txpool.allTxs(), txpool.deferredTxs...)
+		if err := txpool.journal.rotate(txs); err != nil {
+			txpool.log.Warn("Failed to rotate tx journal", "err", err)
+		}
+		if err := txpool.journal.close(); err != nil {
+			txpool.log.Warn("Failed to close tx journal", "err", err)
+		}
+		txpool.journal = nil
+	})
+}
+
+// rotateJournal rewrites the journal with currently known txs to keep it compact
+func (txpool *TxPool) rotateJournal() {
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+	// the journal is closed if the ticker fired concurrently with Stop
+	if txpool.journal == nil {
+		return
+	}
+	txs := append(txpool.allTxs(), txpool.deferredTxs...)
+	if err := txpool.journal.rotate(txs); err != nil {
+		txpool.log.Warn("Failed to rotate tx journal", "err", err)
+	}
+}
+
+func (txpool *TxPool) journalTx(tx *types.Transaction) {
+	if txpool.journal == nil {
+		return
+	}
+	if err := txpool.journal.insert(tx); err != nil {
+		txpool.log.Warn("Failed to journal tx", "hash", tx.Hash().Hex(), "err", err)
+	}
 }
 
 func (txpool *TxPool) addDeferredTx(tx *types.Transaction) {
@@ -88,7 +188,10 @@ func (txpool *TxPool) addDeferredTx(tx *types.Transaction) {
 		return
 	}
 	txpool.deferredTxs = append(txpool.deferredTxs, tx)
+	txpool.arrivals[tx.Hash()] = time.Now()
+	txpool.journalTx(tx)
 	if len(txpool.deferredTxs) > MaxDeferredTxs {
+		delete(txpool.arrivals, txpool.deferredTxs[0].Hash())
 		txpool.deferredTxs[0] = nil
 		txpool.deferredTxs = txpool.deferredTxs[1:]
 	}
@@ -98,37 +201,130 @@ func (txpool *TxPool) addDeferredTx(tx *types.Transaction) {
 func (txpool *TxPool) Validate(tx *types.Transaction) error {
 	txpool.mutex.Lock()
 	defer txpool.mutex.Unlock()
-	return txpool.validate(tx)
+	_, _, err := txpool.validate(tx)
+	return err
 }
 
-func (txpool *TxPool) validate(tx *types.Transaction) error {
-
-	if err := txpool.checkTotalTxLimit(); err != nil {
-		return err
-	}
+// validate returns pending txs which should be replaced or evicted to accept the given one
+func (txpool *TxPool) validate(tx *types.Transaction) (replaced *types.Transaction, evicted *types.Transaction, err error) {
 
 	hash := tx.Hash()
 
 	if _, ok := txpool.pending[hash]; ok {
-		return DuplicateTxError
+		return nil, nil, DuplicateTxError
+	}
+	if _, ok := txpool.queued[hash]; ok {
+		return nil, nil, DuplicateTxError
 	}
 
 	sender, _ := types.Sender(tx)
 
-	if err := txpool.checkAddrTxLimit(sender); err != nil {
-		return err
+	replaced, err = txpool.findReplaced(sender, tx)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := txpool.checkAddrCeremonyTx(tx); err != nil {
-		return err
+	if replaced == nil {
+		if evicted, err = txpool.checkLimits(sender, tx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if replaced == nil || replaced.Type != tx.Type {
+		if err := txpool.checkAddrCeremonyTx(tx); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	appState := txpool.appState.Readonly(txpool.head.Height())
 
 	if appState == nil {
-		return errors.New("tx can't be validated")
+		return nil, nil, errors.New("tx can't be validated")
+	}
+	if err := validation.ValidateTx(appState, tx, txpool.minFeePerByte, true); err != nil {
+		return nil, nil, err
+	}
+	return replaced, evicted, nil
+}
+
+// checkLimits returns pending tx which should be evicted if the pool or the sender's queue is full
+func (txpool *TxPool) checkLimits(sender common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	if err := txpool.checkAddrTxLimit(sender); err != nil {
+		// only ceremony txs are allowed to push out txs of the same sender
+		if !priorityTypes[tx.Type] {
+			return nil, err
+		}
+		if evicted := txpool.findEvictionCandidate(sender, tx, true); evicted != nil {
+			return evicted, nil
+		}
+		return nil, err
+	}
+	if err := txpool.checkTotalTxLimit(); err != nil {
+		if evicted := txpool.findEvictionCandidate(sender, tx, false); evicted != nil {
+			return evicted, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// findEvictionCandidate returns the cheapest pending tx which can be evicted in favour of the given one.
+// Only the last tx of a sender can be evicted to avoid nonce gaps, ceremony txs are never evicted.
+// Txs with equal fee per byte are evicted from the longest queue first.
+func (txpool *TxPool) findEvictionCandidate(sender common.Address, tx *types.Transaction, ownQueue bool) *types.Transaction {
+	var candidate *types.Transaction
+	if ownQueue {
+		if last := lastTx(txpool.senderTxs(sender)); last != nil && !priorityTypes[last.Type] {
+			candidate = last
+		}
+	} else {
+		candidate = txpool.evictionQueue.cheapest(sender)
+	}
+	if candidate == nil {
+		return nil
+	}
+	if !priorityTypes[tx.Type] && compareFeePerByte(tx, candidate) <= 0 {
+		return nil
+	}
+	return candidate
+}
+
+func lastTx(txs []*types.Transaction) *types.Transaction {
+	var last *types.Transaction
+	for _, tx := range txs {
+		if last == nil || tx.Epoch > last.Epoch || tx.Epoch == last.Epoch && tx.AccountNonce > last.AccountNonce {
+			last = tx
+		}
+	}
+	return last
+}
+
+// compareFeePerByte compares MaxFee/Size of the txs without losing precision
+func compareFeePerByte(a, b *types.Transaction) int {
+	left := new(big.Int).Mul(a.MaxFeeOrZero(), big.NewInt(int64(b.Size())))
+	right := new(big.Int).Mul(b.MaxFeeOrZero(), big.NewInt(int64(a.Size())))
+	return left.Cmp(right)
+}
+
+// findReplaced returns pending tx of the sender with the same nonce and epoch,
+// an error is returned if the new tx does not pay enough to replace it
+func (txpool *TxPool) findReplaced(sender common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	for _, existingTx := range txpool.senderTxs(sender) {
+		if existingTx.AccountNonce != tx.AccountNonce || existingTx.Epoch != tx.Epoch {
+			continue
+		}
+		if !txpool.isPriceBumped(existingTx.MaxFeeOrZero(), tx.MaxFeeOrZero()) ||
+			!txpool.isPriceBumped(existingTx.TipsOrZero(), tx.TipsOrZero()) {
+			return nil, ReplacementUnderpriced
+		}
+		return existingTx, nil
 	}
-	return validation.ValidateTx(appState, tx, txpool.minFeePerByte, true)
+	return nil, nil
+}
+
+func (txpool *TxPool) isPriceBumped(oldPrice, newPrice *big.Int) bool {
+	threshold := new(big.Int).Mul(oldPrice, big.NewInt(int64(100+txpool.cfg.TxPriceBump)))
+	return new(big.Int).Mul(newPrice, big.NewInt(100)).Cmp(threshold) >= 0
 }
 
 func (txpool *TxPool) Add(tx *types.Transaction) error {
@@ -143,22 +339,26 @@ func (txpool *TxPool) Add(tx *types.Transaction) error {
 		return nil
 	}
 
-	if err := txpool.validate(tx); err != nil {
+	replaced, evicted, err := txpool.validate(tx)
+	if err != nil {
 		if err != DuplicateTxError && sender == txpool.coinbase {
 			log.Warn("Tx is not valid", "hash", tx.Hash().Hex(), "err", err)
 		}
 		return err
 	}
 
+	if replaced != nil {
+		txpool.remove(replaced)
+	}
+	if evicted != nil {
+		txpool.drop(evicted, DropReasonEvicted)
+		txpool.rollbackNonce(evicted)
+	}
+
 	hash := tx.Hash()
 
-	txpool.pending[hash] = tx
-	senderPending := txpool.pendingPerAddr[sender]
-	if senderPending == nil {
-		senderPending = make(map[common.Hash]*types.Transaction)
-		txpool.pendingPerAddr[sender] = senderPending
-	}
-	senderPending[hash] = tx
+	txpool.insert(sender, tx)
+	txpool.journalTx(tx)
 
 	txpool.appState.NonceCache.SetNonce(sender, tx.Epoch, tx.AccountNonce)
 	if txpool.tmpNonceCache != nil {
@@ -170,18 +370,34 @@ func (txpool *TxPool) Add(tx *types.Transaction) error {
 		Own: sender == txpool.coinbase,
 	})
 
+	if replaced != nil {
+		txpool.log.Debug("Tx replaced", "old", replaced.Hash().Hex(), "new", hash.Hex())
+		txpool.bus.Publish(&events.TxReplacedEvent{
+			Old: replaced,
+			New: tx,
+		})
+	}
+
 	return nil
 }
 
+// GetPendingTransaction returns both executable and queued txs
 func (txpool *TxPool) GetPendingTransaction() []*types.Transaction {
 	txpool.mutex.Lock()
 	defer txpool.mutex.Unlock()
 
+	return txpool.allTxs()
+}
+
+func (txpool *TxPool) allTxs() []*types.Transaction {
 	var list []*types.Transaction
 
 	for _, tx := range txpool.pending {
 		list = append(list, tx)
 	}
+	for _, tx := range txpool.queued {
+		list = append(list, tx)
+	}
 	return list
 }
 
@@ -189,20 +405,17 @@ func (txpool *TxPool) GetPendingByAddress(address common.Address) []*types.Trans
 	txpool.mutex.Lock()
 	defer txpool.mutex.Unlock()
 
-	var list []*types.Transaction
-
-	for _, tx := range txpool.pendingPerAddr[address] {
-		list = append(list, tx)
-	}
-	return list
+	return txpool.senderTxs(address)
 }
 
 func (txpool *TxPool) GetTx(hash common.Hash) *types.Transaction {
 	txpool.mutex.Lock()
 	defer txpool.mutex.Unlock()
 
-	tx, ok := txpool.pending[hash]
-	if ok {
+	if tx, ok := txpool.pending[hash]; ok {
+		return tx
+	}
+	if tx, ok := txpool.queued[hash]; ok {
 		return tx
 	}
 	return nil
@@ -212,19 +425,49 @@ func (txpool *TxPool) BuildBlockTransactions() []*types.Transaction {
 	ctx := txpool.createBuildingContext()
 	ctx.addPriorityTxsToBlock()
 	ctx.addTxsToBlock()
+
+	result := &buildingResult{
+		time:     time.Now(),
+		included: make(map[common.Hash]struct{}, len(ctx.blockTxs)),
+		skipped:  ctx.skipped,
+	}
+	for _, tx := range ctx.blockTxs {
+		result.included[tx.Hash()] = struct{}{}
+	}
+	txpool.mutex.Lock()
+	txpool.lastBuild = result
+	txpool.mutex.Unlock()
+
 	return ctx.blockTxs
 }
 
 func (txpool *TxPool) Remove(transaction *types.Transaction) {
 	txpool.mutex.Lock()
 	defer txpool.mutex.Unlock()
-	delete(txpool.pending, transaction.Hash())
+	txpool.remove(transaction)
+}
 
+func (txpool *TxPool) remove(transaction *types.Transaction) {
 	sender, _ := types.Sender(transaction)
-	senderPending := txpool.pendingPerAddr[sender]
-	delete(senderPending, transaction.Hash())
-	if len(senderPending) == 0 {
-		delete(txpool.pendingPerAddr, sender)
+	removeFrom(txpool.pending, txpool.pendingPerAddr, sender, transaction.Hash())
+	removeFrom(txpool.queued, txpool.queuedPerAddr, sender, transaction.Hash())
+	delete(txpool.arrivals, transaction.Hash())
+	txpool.updateEvictionQueue(sender)
+}
+
+// rollbackNonce sets the nonce cache to the last tx of the sender left in the pool,
+// so the next tx built by the node does not make a gap in place of the removed one
+func (txpool *TxPool) rollbackNonce(removed *types.Transaction) {
+	sender, _ := types.Sender(removed)
+	var nonce uint32
+	for _, tx := range txpool.senderTxs(sender) {
+		if tx.Epoch == removed.Epoch && tx.AccountNonce > nonce {
+			nonce = tx.AccountNonce
+		}
+	}
+	txpool.appState.NonceCache.ResetNonce(sender, removed.Epoch, nonce)
+	if txpool.tmpNonceCache != nil {
+		txpool.tmpNonceCache.ResetNonce(sender, removed.Epoch, nonce)
 	}
 }
 
@@ -236,12 +479,17 @@ func (txpool *TxPool) ResetTo(block *types.Block) {
 		txpool.Remove(tx)
 	}
 
+	globalEpoch := txpool.appState.State.Epoch()
+
+	if globalEpoch != txpool.epoch {
+		txpool.epoch = globalEpoch
+		txpool.purgeStaleEpoch(globalEpoch)
+	}
+
 	txpool.mutex.Lock()
 	txpool.tmpNonceCache = state.NewNonceCache(txpool.appState.State)
 	txpool.mutex.Unlock()
 
-	globalEpoch := txpool.appState.State.Epoch()
-
 	pending := txpool.GetPendingTransaction()
 
 	appState := txpool.appState.Readonly(txpool.head.Height())
@@ -255,7 +503,7 @@ func (txpool *TxPool) ResetTo(block *types.Block) {
 
 		if err := validation.ValidateTx(appState, tx, txpool.minFeePerByte, true); err != nil {
 			if errors.Cause(err) == validation.InvalidNonce {
-				txpool.Remove(tx)
+				txpool.dropLocked(tx, DropReasonUsedNonce)
 				continue
 			}
 			sender, _ := types.Sender(tx)
@@ -272,7 +520,7 @@ func (txpool *TxPool) ResetTo(block *types.Block) {
 
 	for _, tx := range pending {
 		if tx.Epoch < globalEpoch {
-			txpool.Remove(tx)
+			txpool.dropLocked(tx, DropReasonStaleEpoch)
 			continue
 		}
 		if tx.Epoch > globalEpoch {
@@ -282,8 +530,7 @@ func (txpool *TxPool) ResetTo(block *types.Block) {
 		sender, _ := types.Sender(tx)
 
 		if n, ok := minErrorNonce[sender]; ok && tx.AccountNonce >= n {
-			txpool.Remove(tx)
-			txpool.log.Info("Tx removed by nonce", "err", tx.Hash())
+			txpool.dropLocked(tx, DropReasonInvalidChain)
 			continue
 		}
 
@@ -292,18 +539,19 @@ func (txpool *TxPool) ResetTo(block *types.Block) {
 	txpool.mutex.Lock()
 	txpool.appState.NonceCache = txpool.tmpNonceCache
 	txpool.tmpNonceCache = nil
+	txpool.reorganize()
 	txpool.mutex.Unlock()
 }
 
 func (txpool *TxPool) checkTotalTxLimit() error {
-	if txpool.totalTxLimit > 0 && len(txpool.pending) >= txpool.totalTxLimit {
+	if txpool.totalTxLimit > 0 && len(txpool.pending)+len(txpool.queued) >= txpool.totalTxLimit {
 		return errors.New("tx queue max size reached")
 	}
 	return nil
 }
 
 func (txpool *TxPool) checkAddrTxLimit(sender common.Address) error {
-	if txpool.addrTxLimit > 0 && len(txpool.pendingPerAddr[sender]) >= txpool.addrTxLimit {
+	if txpool.addrTxLimit > 0 && len(txpool.pendingPerAddr[sender])+len(txpool.queuedPerAddr[sender]) >= txpool.addrTxLimit {
 		return errors.New("address tx queue max size reached")
 	}
 	return nil
@@ -314,7 +562,7 @@ func (txpool *TxPool) checkAddrCeremonyTx(tx *types.Transaction) error {
 		return nil
 	}
 	sender, _ := types.Sender(tx)
-	for _, existingTx := range txpool.pendingPerAddr[sender] {
+	for _, existingTx := range txpool.senderTxs(sender) {
 		if existingTx.Type == tx.Type {
 			return errors.New("multiple ceremony transaction")
 		}
@@ -372,7 +620,11 @@ func (txpool *TxPool) StopSync(block *types.Block) {
 	txpool.isSyncing = false
 	txpool.ResetTo(block)
 	for _, tx := range txpool.deferredTxs {
-		txpool.Add(tx)
+		if err := txpool.Add(tx); err != nil {
+			txpool.mutex.Lock()
+			delete(txpool.arrivals, tx.Hash())
+			txpool.mutex.Unlock()
+		}
 	}
 	txpool.deferredTxs = make([]*types.Transaction, 0)
 	txpool.knownDeferredTxs.Clear()
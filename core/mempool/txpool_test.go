@@ -1,17 +1,24 @@
 package mempool
 
 import (
+	"crypto/ecdsa"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
 	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/events"
 	"github.com/idena-network/idena-go/secstore"
 	"github.com/idena-network/idena-go/tests"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tm-db"
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestTxPool_checkTotalTxLimit(t *testing.T) {
@@ -92,7 +99,7 @@ func TestTxPool_addDeferredTx(t *testing.T) {
 	key, _ := crypto.GenerateKey()
 	secStore := secstore.NewSecStore()
 	secStore.AddKey(crypto.FromECDSA(key))
-	pool := NewTxPool(appState, bus, -1, -1, big.NewInt(0))
+	pool := NewTxPool(appState, bus, &config.MempoolConfig{TotalTxLimit: -1, AddrTxLimit: -1}, big.NewInt(0))
 	r := require.New(t)
 
 	key, _ = crypto.GenerateKey()
@@ -144,5 +151,154 @@ func getPool() *TxPool {
 	secStore := secstore.NewSecStore()
 	secStore.AddKey(crypto.FromECDSA(key))
 
-	return NewTxPool(appState, bus, -1, -1, big.NewInt(0))
+	return NewTxPool(appState, bus, &config.MempoolConfig{TotalTxLimit: -1, AddrTxLimit: -1}, big.NewInt(0))
+}
+
+func TestTxJournal(t *testing.T) {
+	r := require.New(t)
+	dir, err := ioutil.TempDir("", "journal")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	key, _ := crypto.GenerateKey()
+	getTx := func(nonce uint32) *types.Transaction {
+		tx, _ := types.SignTx(&types.Transaction{AccountNonce: nonce, Type: types.SendTx}, key)
+		return tx
+	}
+
+	journal := newTxJournal(filepath.Join(dir, "mempool.rlp"))
+	r.Equal(errNoActiveJournal, journal.insert(getTx(1)))
+	r.NoError(journal.load(func(tx *types.Transaction) error {
+		r.Fail("journal should be empty")
+		return nil
+	}))
+
+	r.NoError(journal.rotate([]*types.Transaction{getTx(1), getTx(2)}))
+	r.NoError(journal.insert(getTx(3)))
+	r.NoError(journal.close())
+
+	var loaded []*types.Transaction
+	r.NoError(journal.load(func(tx *types.Transaction) error {
+		loaded = append(loaded, tx)
+		return nil
+	}))
+	r.Len(loaded, 3)
+	r.Equal(getTx(3).Hash(), loaded[2].Hash())
+
+	pool := getPool()
+	pool.loadJournal(filepath.Join(dir, "pool.rlp"))
+	pool.Stop()
+	r.Nil(pool.journal)
+	// the rejournal ticker may fire after the pool is stopped
+	pool.rotateJournal()
+}
+
+func TestOwnTxWatcher(t *testing.T) {
+	r := require.New(t)
+	bus := eventbus.New()
+	appState := appstate.NewAppState(db.NewMemDB(), bus)
+	key, _ := crypto.GenerateKey()
+
+	watcher := NewOwnTxWatcher(&config.MempoolConfig{}, bus, nil, appState, nil)
+	watcher.Start()
+
+	tx1, _ := types.SignTx(&types.Transaction{AccountNonce: 1, Type: types.SendTx}, key)
+	tx2, _ := types.SignTx(&types.Transaction{AccountNonce: 2, Type: types.SendTx}, key)
+	tx3, _ := types.SignTx(&types.Transaction{AccountNonce: 2, Type: types.SendTx, MaxFee: big.NewInt(1)}, key)
+
+	bus.Publish(&events.NewTxEvent{Tx: tx1, Own: true})
+	bus.Publish(&events.NewTxEvent{Tx: tx2, Own: true})
+	bus.Publish(&events.NewTxEvent{Tx: tx2, Own: true})
+	bus.Publish(&events.NewTxEvent{Tx: tests.GetTx(1, 0, key), Own: false})
+
+	ownTxs := watcher.GetOwnTxs()
+	r.Len(ownTxs, 2)
+
+	bus.Publish(&events.TxReplacedEvent{Old: tx2, New: tx3})
+	bus.Publish(&events.TxDroppedEvent{Tx: tx1, Reason: DropReasonExpired, Own: true})
+
+	states := make(map[common.Hash]OwnTx)
+	for _, ownTx := range watcher.GetOwnTxs() {
+		states[ownTx.Tx.Hash()] = ownTx
+	}
+	r.Equal(OwnTxDropped, states[tx1.Hash()].State)
+	r.Equal(DropReasonExpired, states[tx1.Hash()].DropReason)
+	r.Equal(OwnTxReplaced, states[tx2.Hash()].State)
+	r.Equal(tx3.Hash(), *states[tx2.Hash()].ReplacedBy)
+	r.Equal(2, states[tx2.Hash()].Broadcasts)
+}
+
+func TestBumpPrice(t *testing.T) {
+	r := require.New(t)
+	r.Zero(bumpPrice(big.NewInt(0), 10).Sign())
+	r.Equal(big.NewInt(110), bumpPrice(big.NewInt(100), 10))
+	r.Equal(big.NewInt(2), bumpPrice(big.NewInt(1), 10))
+}
+
+func TestTxPool_Eviction(t *testing.T) {
+	r := require.New(t)
+	bus := eventbus.New()
+	appState := appstate.NewAppState(db.NewMemDB(), bus)
+
+	var keys []*ecdsa.PrivateKey
+	var addrs []common.Address
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		addrs = append(addrs, crypto.PubkeyToAddress(key.PublicKey))
+		appState.State.SetBalance(addrs[i], new(big.Int).Mul(common.DnaBase, big.NewInt(100)))
+	}
+	appState.Commit(nil)
+	appState.Initialize(0)
+
+	pool := NewTxPool(appState, bus, &config.MempoolConfig{TotalTxLimit: 3, AddrTxLimit: -1}, big.NewInt(0))
+	pool.Initialize(&types.Header{ProposedHeader: &types.ProposedHeader{}}, common.Address{}, "")
+
+	getTx := func(key *ecdsa.PrivateKey, nonce uint32, maxFee int64) *types.Transaction {
+		to := common.Address{0x1}
+		tx, _ := types.SignTx(&types.Transaction{
+			AccountNonce: nonce,
+			Type:         types.SendTx,
+			To:           &to,
+			MaxFee:       new(big.Int).Mul(common.DnaBase, big.NewInt(maxFee)),
+		}, key)
+		return tx
+	}
+
+	r.NoError(pool.Add(getTx(keys[0], 1, 10)))
+	r.NoError(pool.Add(getTx(keys[0], 2, 10)))
+	cheapest := getTx(keys[1], 1, 5)
+	r.NoError(pool.Add(cheapest))
+
+	r.NoError(pool.Add(getTx(keys[2], 1, 7)))
+	r.Nil(pool.GetTx(cheapest.Hash()))
+	r.Equal(uint32(0), appState.NonceCache.GetNonce(addrs[1], 0))
+
+	r.Error(pool.Add(getTx(keys[2], 2, 1)))
+
+	// the own tail of the sender is not evicted in favour of its next tx
+	r.NoError(pool.Add(getTx(keys[2], 2, 20)))
+	r.Len(pool.GetPendingByAddress(addrs[0]), 1)
+	r.Len(pool.GetPendingByAddress(addrs[2]), 2)
+	r.Equal(uint32(1), appState.NonceCache.GetNonce(addrs[0], 0))
+	r.Equal(uint32(2), appState.NonceCache.GetNonce(addrs[2], 0))
+}
+
+func TestTxPool_purgeExpired(t *testing.T) {
+	r := require.New(t)
+	pool := getPool()
+	pool.cfg.TxLifetime = time.Minute
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	sendTx, _ := types.SignTx(&types.Transaction{AccountNonce: 1, Type: types.SendTx}, key)
+	answersTx, _ := types.SignTx(&types.Transaction{AccountNonce: 2, Type: types.SubmitAnswersHashTx}, key)
+	pool.insert(sender, sendTx)
+	pool.insert(sender, answersTx)
+	pool.arrivals[sendTx.Hash()] = time.Now().Add(-time.Hour)
+	pool.arrivals[answersTx.Hash()] = time.Now().Add(-time.Hour)
+
+	pool.purgeExpired()
+	r.Nil(pool.GetTx(sendTx.Hash()))
+	r.NotNil(pool.GetTx(answersTx.Hash()))
 }
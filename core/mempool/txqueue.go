@@ -0,0 +1,190 @@
+package mempool
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"sort"
+	"time"
+)
+
+// maxReportedGaps restricts the number of missing nonces reported per sender
+const maxReportedGaps = 100
+
+// SenderTxs describes txs of a single sender: executable ones form a contiguous nonce sequence after the state nonce,
+// queued ones wait for missing nonces or for the next epoch
+type SenderTxs struct {
+	Executable    []*types.Transaction
+	Queued        []*types.Transaction
+	MissingNonces []uint32
+}
+
+func (txpool *TxPool) GetSenderTxs(address common.Address) *SenderTxs {
+	txpool.mutex.Lock()
+	defer txpool.mutex.Unlock()
+
+	res := &SenderTxs{}
+	for _, tx := range txpool.pendingPerAddr[address] {
+		res.Executable = append(res.Executable, tx)
+	}
+	for _, tx := range txpool.queuedPerAddr[address] {
+		res.Queued = append(res.Queued, tx)
+	}
+	sortByNonce(res.Executable)
+	sortByNonce(res.Queued)
+
+	globalEpoch := txpool.appState.State.Epoch()
+	nonce := txpool.executableNonce(address)
+	for _, tx := range res.Queued {
+		if tx.Epoch != globalEpoch {
+			continue
+		}
+		for nonce+1 < tx.AccountNonce && len(res.MissingNonces) < maxReportedGaps {
+			nonce++
+			res.MissingNonces = append(res.MissingNonces, nonce)
+		}
+		nonce = tx.AccountNonce
+	}
+	return res
+}
+
+func sortByNonce(txs []*types.Transaction) {
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].Epoch != txs[j].Epoch {
+			return txs[i].Epoch < txs[j].Epoch
+		}
+		return txs[i].AccountNonce < txs[j].AccountNonce
+	})
+}
+
+// senderTxs returns both executable and queued txs of the sender
+func (txpool *TxPool) senderTxs(sender common.Address) []*types.Transaction {
+	var list []*types.Transaction
+	for _, tx := range txpool.pendingPerAddr[sender] {
+		list = append(list, tx)
+	}
+	for _, tx := range txpool.queuedPerAddr[sender] {
+		list = append(list, tx)
+	}
+	return list
+}
+
+func (txpool *TxPool) senders() map[common.Address]struct{} {
+	res := make(map[common.Address]struct{}, len(txpool.pendingPerAddr)+len(txpool.queuedPerAddr))
+	for addr := range txpool.pendingPerAddr {
+		res[addr] = struct{}{}
+	}
+	for addr := range txpool.queuedPerAddr {
+		res[addr] = struct{}{}
+	}
+	return res
+}
+
+// stateNonce returns the nonce of the sender's last mined tx in the current epoch
+func (txpool *TxPool) stateNonce(sender common.Address) uint32 {
+	if txpool.appState.State.GetEpoch(sender) < txpool.appState.State.Epoch() {
+		return 0
+	}
+	return txpool.appState.State.GetNonce(sender)
+}
+
+// executableNonce returns the nonce of the sender's last executable tx
+func (txpool *TxPool) executableNonce(sender common.Address) uint32 {
+	globalEpoch := txpool.appState.State.Epoch()
+	nonce := txpool.stateNonce(sender)
+	for _, tx := range txpool.pendingPerAddr[sender] {
+		if tx.Epoch == globalEpoch && tx.AccountNonce > nonce {
+			nonce = tx.AccountNonce
+		}
+	}
+	return nonce
+}
+
+func (txpool *TxPool) isExecutable(sender common.Address, tx *types.Transaction) bool {
+	return tx.Epoch == txpool.appState.State.Epoch() && tx.AccountNonce <= txpool.executableNonce(sender)+1
+}
+
+func (txpool *TxPool) insert(sender common.Address, tx *types.Transaction) {
+	if _, ok := txpool.arrivals[tx.Hash()]; !ok {
+		txpool.arrivals[tx.Hash()] = time.Now()
+	}
+	if txpool.isExecutable(sender, tx) {
+		txpool.insertTo(txpool.pending, txpool.pendingPerAddr, sender, tx)
+		txpool.promoteQueued(sender)
+	} else {
+		txpool.insertTo(txpool.queued, txpool.queuedPerAddr, sender, tx)
+	}
+	txpool.updateEvictionQueue(sender)
+}
+
+func (txpool *TxPool) updateEvictionQueue(sender common.Address) {
+	txs := txpool.senderTxs(sender)
+	txpool.evictionQueue.update(sender, lastTx(txs), len(txs))
+}
+
+func (txpool *TxPool) insertTo(all map[common.Hash]*types.Transaction, perAddr map[common.Address]map[common.Hash]*types.Transaction,
+	sender common.Address, tx *types.Transaction) {
+	hash := tx.Hash()
+	all[hash] = tx
+	senderTxs := perAddr[sender]
+	if senderTxs == nil {
+		senderTxs = make(map[common.Hash]*types.Transaction)
+		perAddr[sender] = senderTxs
+	}
+	senderTxs[hash] = tx
+}
+
+func removeFrom(all map[common.Hash]*types.Transaction, perAddr map[common.Address]map[common.Hash]*types.Transaction,
+	sender common.Address, hash common.Hash) {
+	delete(all, hash)
+	senderTxs := perAddr[sender]
+	delete(senderTxs, hash)
+	if len(senderTxs) == 0 {
+		delete(perAddr, sender)
+	}
+}
+
+// promoteQueued moves queued txs of the sender which fill the nonce sequence to the executable set
+func (txpool *TxPool) promoteQueued(sender common.Address) {
+	globalEpoch := txpool.appState.State.Epoch()
+	for {
+		next := txpool.executableNonce(sender) + 1
+		var promoted *types.Transaction
+		for _, tx := range txpool.queuedPerAddr[sender] {
+			if tx.Epoch == globalEpoch && tx.AccountNonce == next {
+				promoted = tx
+				break
+			}
+		}
+		if promoted == nil {
+			return
+		}
+		removeFrom(txpool.queued, txpool.queuedPerAddr, sender, promoted.Hash())
+		txpool.insertTo(txpool.pending, txpool.pendingPerAddr, sender, promoted)
+	}
+}
+
+// reorganize splits txs of every sender into executable and queued sets again,
+// it should be called after the state has been changed
+func (txpool *TxPool) reorganize() {
+	globalEpoch := txpool.appState.State.Epoch()
+	for sender := range txpool.senders() {
+		txs := txpool.senderTxs(sender)
+		sortByNonce(txs)
+		delete(txpool.pendingPerAddr, sender)
+		delete(txpool.queuedPerAddr, sender)
+		nonce := txpool.stateNonce(sender)
+		for _, tx := range txs {
+			hash := tx.Hash()
+			delete(txpool.pending, hash)
+			delete(txpool.queued, hash)
+			if tx.Epoch == globalEpoch && tx.AccountNonce <= nonce+1 {
+				txpool.insertTo(txpool.pending, txpool.pendingPerAddr, sender, tx)
+				if tx.AccountNonce > nonce {
+					nonce = tx.AccountNonce
+				}
+			} else {
+				txpool.insertTo(txpool.queued, txpool.queuedPerAddr, sender, tx)
+			}
+		}
+	}
+}
@@ -13,21 +13,27 @@ import (
 	"github.com/idena-network/idena-go/ipfs"
 	"github.com/idena-network/idena-go/log"
 	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
 	dbm "github.com/tendermint/tm-db"
 	"os"
 	"path/filepath"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
 	SnapshotsFolder = "/snapshots"
+
+	chunkDownloadWorkers  = 8
+	chunkDownloadAttempts = 3
 )
 
 var (
 	InvalidManifestPrefix = []byte("im")
 	MaxManifestTimeouts   = byte(5)
+	DownloadedChunkPrefix = []byte("dc")
 )
 
 type SnapshotManager struct {
@@ -114,9 +120,70 @@ func (m *SnapshotManager) createSnapshot(height uint64) (root common.Hash) {
 	}
 	m.clearFs(filePath)
 	m.writeLastManifest(cid.Bytes(), root, height, filePath)
+	if m.cfg.Sync.SnapshotV2 {
+		go m.createSnapshotV2(height, root)
+	}
 	return root
 }
 
+// createSnapshotV2 publishes chunks of the state of the given height, the manifest is written only if
+// the chunked state has the same root as the v1 snapshot
+func (m *SnapshotManager) createSnapshotV2(height uint64, v1Root common.Hash) {
+	var added [][]byte
+	root, chunks, err := m.state.WriteSnapshotChunks(height, func(data []byte) ([]byte, error) {
+		c, err := m.ipfs.Add(data)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, c.Bytes())
+		return c.Bytes(), nil
+	})
+	if err != nil {
+		m.log.Error("Cannot add snapshot chunks to ipfs", "err", err)
+		m.unpinChunks(added, m.repo.LastSnapshotManifestV2())
+		return
+	}
+	if root != v1Root {
+		m.log.Error("Snapshot v2 root doesn't equal snapshot root", "height", height, "root", root.Hex(), "v1Root", v1Root.Hex())
+		m.unpinChunks(added, m.repo.LastSnapshotManifestV2())
+		return
+	}
+	m.writeLastManifestV2(&snapshot.ManifestV2{
+		Root:   root,
+		Height: height,
+		Chunks: chunks,
+	})
+}
+
+// writeLastManifestV2 replaces the published v2 manifest, chunks which are not used by the new manifest are unpinned
+func (m *SnapshotManager) writeLastManifestV2(manifest *snapshot.ManifestV2) {
+	if prev := m.repo.LastSnapshotManifestV2(); prev != nil {
+		var cids [][]byte
+		for _, chunk := range prev.Chunks {
+			cids = append(cids, chunk.Cid)
+		}
+		m.unpinChunks(cids, manifest)
+	}
+	m.repo.WriteLastSnapshotManifestV2(manifest)
+}
+
+func (m *SnapshotManager) unpinChunks(cids [][]byte, used *snapshot.ManifestV2) {
+	usedCids := make(map[string]struct{})
+	if used != nil {
+		for _, chunk := range used.Chunks {
+			usedCids[string(chunk.Cid)] = struct{}{}
+		}
+	}
+	for _, c := range cids {
+		if _, ok := usedCids[string(c)]; ok {
+			continue
+		}
+		if err := m.ipfs.Unpin(c); err != nil {
+			m.log.Warn("Cannot unpin snapshot chunk", "err", err)
+		}
+	}
+}
+
 func (m *SnapshotManager) clearFs(excludedFile string) {
 	if prevCid, _, _, _ := m.repo.LastSnapshotManifest(); prevCid != nil {
 		m.ipfs.Unpin(prevCid)
@@ -199,6 +266,124 @@ func (m *SnapshotManager) DownloadSnapshot(snapshot *snapshot.Manifest) (filePat
 	return filePath, loadToErr
 }
 
+// DownloadSnapshotV2 loads chunks of the v2 snapshot in parallel, ipfs requests every chunk from all its providers.
+// Every chunk is verified and pinned right after its download, so the download is resumed after a failure
+// and a corrupted chunk is detected before the state recovery.
+func (m *SnapshotManager) DownloadSnapshotV2(manifest *snapshot.Manifest) error {
+	if err := manifest.V2().Validate(); err != nil {
+		return err
+	}
+	var pending []int
+	for i := range manifest.Chunks {
+		if !m.db.Has(m.downloadedChunkKey(manifest, i)) {
+			pending = append(pending, i)
+		}
+	}
+	total := len(manifest.Chunks)
+	if len(pending) < total {
+		m.log.Info("Resume snapshot loading", "loaded", total-len(pending), "chunks", total)
+	}
+
+	jobs := make(chan int, len(pending))
+	for _, idx := range pending {
+		jobs <- idx
+	}
+	close(jobs)
+
+	var loaded = int32(total - len(pending))
+	var firstErr error
+	var errOnce sync.Once
+	stop := make(chan struct{})
+	logStep := total/10 + 1
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < chunkDownloadWorkers && i < len(pending); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if err := m.downloadChunk(manifest, idx); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						close(stop)
+					})
+					return
+				}
+				if n := atomic.AddInt32(&loaded, 1); int(n)%logStep == 0 {
+					m.log.Info("Snapshot loading", "progress", fmt.Sprintf("%v%%", int(n)*100/total))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		if errors.Cause(firstErr) == snapshot.ErrInvalidChunk {
+			m.dropDownloadedChunks(manifest)
+		}
+		return firstErr
+	}
+	m.writeLastManifestV2(manifest.V2())
+	for i := range manifest.Chunks {
+		m.db.Delete(m.downloadedChunkKey(manifest, i))
+	}
+	return nil
+}
+
+func (m *SnapshotManager) downloadChunk(manifest *snapshot.Manifest, idx int) error {
+	chunk := manifest.Chunks[idx]
+	var data []byte
+	var err error
+	for attempt := 0; attempt < chunkDownloadAttempts; attempt++ {
+		if data, err = m.ipfs.Get(chunk.Cid); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "cannot load snapshot chunk %v", idx)
+	}
+	if _, err := chunk.Decode(data); err != nil {
+		return errors.Wrapf(err, "chunk %v", idx)
+	}
+	if err := m.ipfs.Pin(chunk.Cid); err != nil {
+		return errors.Wrapf(err, "cannot pin snapshot chunk %v", idx)
+	}
+	m.db.Set(m.downloadedChunkKey(manifest, idx), []byte{0x1})
+	return nil
+}
+
+// LoadChunk reads the downloaded chunk of the v2 snapshot and verifies it again
+func (m *SnapshotManager) LoadChunk(manifest *snapshot.Manifest, idx int) (*snapshot.Block, error) {
+	chunk := manifest.Chunks[idx]
+	data, err := m.ipfs.Get(chunk.Cid)
+	if err != nil {
+		return nil, err
+	}
+	return chunk.Decode(data)
+}
+
+func (m *SnapshotManager) dropDownloadedChunks(manifest *snapshot.Manifest) {
+	var cids [][]byte
+	for i, chunk := range manifest.Chunks {
+		key := m.downloadedChunkKey(manifest, i)
+		if m.db.Has(key) {
+			cids = append(cids, chunk.Cid)
+			m.db.Delete(key)
+		}
+	}
+	m.unpinChunks(cids, m.repo.LastSnapshotManifestV2())
+}
+
+func (m *SnapshotManager) downloadedChunkKey(manifest *snapshot.Manifest, idx int) []byte {
+	key := append(DownloadedChunkPrefix, manifest.Key()...)
+	return append(key, []byte(strconv.Itoa(idx))...)
+}
+
 func (m *SnapshotManager) StartSync() {
 	m.isSyncing = true
 }
@@ -215,16 +400,28 @@ func (m *SnapshotManager) IsInvalidManifest(cid []byte) bool {
 	return m.db.Get(key)[0] >= MaxManifestTimeouts
 }
 
-func (m *SnapshotManager) AddInvalidManifest(cid []byte) {
-	key := append(InvalidManifestPrefix, cid...)
+func (m *SnapshotManager) AddInvalidManifest(manifest *snapshot.Manifest) {
+	key := append(InvalidManifestPrefix, manifest.Key()...)
 	m.db.Set(key, []byte{MaxManifestTimeouts})
+	m.releaseManifest(manifest)
 }
-func (m *SnapshotManager) AddTimeoutManifest(cid []byte) {
-	key := append(InvalidManifestPrefix, cid...)
+
+func (m *SnapshotManager) AddTimeoutManifest(manifest *snapshot.Manifest) {
+	key := append(InvalidManifestPrefix, manifest.Key()...)
 	value := []byte{0x1}
 	if m.db.Has(key) {
 		value = m.db.Get(key)
 		value[0]++
 	}
 	m.db.Set(key, value)
+	if value[0] >= MaxManifestTimeouts {
+		m.releaseManifest(manifest)
+	}
+}
+
+// releaseManifest drops download progress of the abandoned v2 manifest and unpins its downloaded chunks
+func (m *SnapshotManager) releaseManifest(manifest *snapshot.Manifest) {
+	if manifest.IsV2() {
+		m.dropDownloadedChunks(manifest)
+	}
 }
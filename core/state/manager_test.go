@@ -1,30 +1,107 @@
 package state
 
 import (
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/core/state/snapshot"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/ipfs"
+	"github.com/idena-network/idena-go/log"
 	"github.com/stretchr/testify/require"
 	db "github.com/tendermint/tm-db"
+	"math/big"
 	"testing"
 )
 
+type unpinRecorder struct {
+	ipfs.Proxy
+	unpinned [][]byte
+}
+
+func (p *unpinRecorder) Unpin(key []byte) error {
+	p.unpinned = append(p.unpinned, key)
+	return nil
+}
+
 func TestSnapshotManager_IsInvalidManifest(t *testing.T) {
 	m := SnapshotManager{
 		db: db.NewMemDB(),
 	}
-	m.AddInvalidManifest([]byte{0x1})
+	m.AddInvalidManifest(&snapshot.Manifest{Cid: []byte{0x1}})
 
-	m.AddTimeoutManifest([]byte{0x3})
-	m.AddTimeoutManifest([]byte{0x3})
-	m.AddTimeoutManifest([]byte{0x3})
-	m.AddTimeoutManifest([]byte{0x3})
-	m.AddTimeoutManifest([]byte{0x3})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x3}})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x3}})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x3}})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x3}})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x3}})
 
-	m.AddTimeoutManifest([]byte{0x4})
-	m.AddTimeoutManifest([]byte{0x4})
-	m.AddTimeoutManifest([]byte{0x4})
-	m.AddTimeoutManifest([]byte{0x4})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x4}})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x4}})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x4}})
+	m.AddTimeoutManifest(&snapshot.Manifest{Cid: []byte{0x4}})
 
 	require.True(t, m.IsInvalidManifest([]byte{0x1}))
 	require.False(t, m.IsInvalidManifest([]byte{0x2}))
 	require.True(t, m.IsInvalidManifest([]byte{0x3}))
 	require.False(t, m.IsInvalidManifest([]byte{0x4}))
 }
+
+func TestSnapshotManager_AddTimeoutManifest(t *testing.T) {
+	require := require.New(t)
+	memdb := db.NewMemDB()
+	proxy := &unpinRecorder{Proxy: ipfs.NewMemoryIpfsProxy()}
+	m := SnapshotManager{
+		db:   db.NewPrefixDB(memdb, database.SnapshotDbPrefix),
+		repo: database.NewRepo(memdb),
+		ipfs: proxy,
+		log:  log.New(),
+	}
+
+	manifest := &snapshot.Manifest{
+		Height: 1,
+		Chunks: []*snapshot.ChunkInfo{{Cid: []byte{0x1}}, {Cid: []byte{0x2}}, {Cid: []byte{0x3}}},
+	}
+	m.db.Set(m.downloadedChunkKey(manifest, 0), []byte{0x1})
+	m.db.Set(m.downloadedChunkKey(manifest, 1), []byte{0x1})
+
+	// downloaded chunks are kept to resume the download until the manifest is abandoned
+	for i := byte(1); i < MaxManifestTimeouts; i++ {
+		m.AddTimeoutManifest(manifest)
+	}
+	require.False(m.IsInvalidManifest(manifest.Key()))
+	require.True(m.db.Has(m.downloadedChunkKey(manifest, 0)))
+	require.Empty(proxy.unpinned)
+
+	m.AddTimeoutManifest(manifest)
+	require.True(m.IsInvalidManifest(manifest.Key()))
+	require.False(m.db.Has(m.downloadedChunkKey(manifest, 0)))
+	require.False(m.db.Has(m.downloadedChunkKey(manifest, 1)))
+	require.Equal([][]byte{{0x1}, {0x2}}, proxy.unpinned)
+}
+
+func TestSnapshotManager_createSnapshotV2(t *testing.T) {
+	require := require.New(t)
+	memdb := db.NewMemDB()
+	stateDb := NewLazy(memdb)
+	for i := 0; i < 10; i++ {
+		stateDb.SetBalance(common.Address{byte(i)}, big.NewInt(int64(i+1)))
+	}
+	stateDb.Commit(true)
+
+	m := SnapshotManager{
+		db:    db.NewPrefixDB(memdb, database.SnapshotDbPrefix),
+		state: stateDb,
+		repo:  database.NewRepo(memdb),
+		ipfs:  ipfs.NewMemoryIpfsProxy(),
+		log:   log.New(),
+	}
+
+	m.createSnapshotV2(1, common.Hash{0x1})
+	require.Nil(m.repo.LastSnapshotManifestV2())
+
+	m.createSnapshotV2(1, stateDb.Root())
+	manifest := m.repo.LastSnapshotManifestV2()
+	require.NotNil(manifest)
+	require.Equal(stateDb.Root(), manifest.Root)
+	require.Equal(uint64(1), manifest.Height)
+	require.NoError(manifest.Validate())
+}
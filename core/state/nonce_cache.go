@@ -54,6 +54,19 @@ func (ns *NonceCache) SetNonce(addr common.Address, txEpoch uint16, nonce uint32
 	}
 }
 
+// ResetNonce sets the nonce even if it is lower than the tracked one,
+// the nonce of the state is still used if it is higher
+func (ns *NonceCache) ResetNonce(addr common.Address, txEpoch uint16, nonce uint32) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	acc := ns.getAccount(addr, txEpoch)
+	acc.nonce = nonce
+	if so := ns.StateDB.getStateAccount(addr); so != nil && so.Epoch() == txEpoch && so.Nonce() > nonce {
+		acc.nonce = so.Nonce()
+	}
+}
+
 // populate the managed state
 func (ns *NonceCache) getAccount(addr common.Address, epoch uint16) *account {
 	if epochs, ok := ns.accounts[addr]; !ok {
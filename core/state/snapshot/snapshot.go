@@ -1,13 +1,21 @@
 package snapshot
 
 import (
+	"bytes"
 	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
 )
 
 const (
 	BlockSize = 10000
 )
 
+var (
+	ErrInvalidChunk = errors.New("invalid snapshot chunk")
+)
+
 type KeyValue struct {
 	Key   []byte
 	Value []byte
@@ -21,6 +29,23 @@ type Manifest struct {
 	Root   common.Hash
 	Height uint64
 	Cid    []byte
+	// Chunks are set for v2 snapshots only, v2 manifests are sent by ManifestV2 to keep v1 peers compatible
+	Chunks []*ChunkInfo `rlp:"-"`
+}
+
+// ChunkInfo describes a separate ipfs object of the v2 snapshot, keys of the chunk are sorted and lie in [FirstKey, LastKey]
+type ChunkInfo struct {
+	Cid      []byte
+	Hash     common.Hash
+	FirstKey []byte
+	LastKey  []byte
+}
+
+// ManifestV2 lists chunks of the snapshot, so every chunk is verified right after its download
+type ManifestV2 struct {
+	Root   common.Hash
+	Height uint64
+	Chunks []*ChunkInfo
 }
 
 func (sb *Block) Full() bool {
@@ -33,3 +58,85 @@ func (sb *Block) Add(key, value []byte) {
 		Value: value,
 	})
 }
+
+func (m *Manifest) IsV2() bool {
+	return len(m.Chunks) > 0
+}
+
+// Key identifies the snapshot, it is the cid for v1 and the hash of the chunk list for v2
+func (m *Manifest) Key() []byte {
+	if !m.IsV2() {
+		return m.Cid
+	}
+	hash := rlp.Hash(m.Chunks)
+	return hash[:]
+}
+
+func (m *Manifest) V2() *ManifestV2 {
+	return &ManifestV2{
+		Root:   m.Root,
+		Height: m.Height,
+		Chunks: m.Chunks,
+	}
+}
+
+func (m *ManifestV2) Manifest() *Manifest {
+	return &Manifest{
+		Root:   m.Root,
+		Height: m.Height,
+		Chunks: m.Chunks,
+	}
+}
+
+// Validate checks that chunks are not empty and their key ranges are sorted and do not overlap
+func (m *ManifestV2) Validate() error {
+	if len(m.Chunks) == 0 {
+		return errors.New("manifest has no chunks")
+	}
+	for i, chunk := range m.Chunks {
+		if len(chunk.Cid) == 0 {
+			return errors.Errorf("chunk %v has no cid", i)
+		}
+		if bytes.Compare(chunk.FirstKey, chunk.LastKey) > 0 {
+			return errors.Errorf("chunk %v has invalid key range", i)
+		}
+		if i > 0 && bytes.Compare(m.Chunks[i-1].LastKey, chunk.FirstKey) >= 0 {
+			return errors.Errorf("chunk %v overlaps the previous chunk", i)
+		}
+	}
+	return nil
+}
+
+func NewChunkInfo(cid []byte, data []byte, sb *Block) *ChunkInfo {
+	return &ChunkInfo{
+		Cid:      cid,
+		Hash:     crypto.Keccak256Hash(data),
+		FirstKey: sb.Data[0].Key,
+		LastKey:  sb.Data[len(sb.Data)-1].Key,
+	}
+}
+
+// Decode verifies the hash of the chunk data and keys of the decoded block
+func (c *ChunkInfo) Decode(data []byte) (*Block, error) {
+	if crypto.Keccak256Hash(data) != c.Hash {
+		return nil, errors.Wrap(ErrInvalidChunk, "hash mismatch")
+	}
+	sb := &Block{}
+	if err := rlp.DecodeBytes(data, sb); err != nil {
+		return nil, errors.Wrap(ErrInvalidChunk, err.Error())
+	}
+	if len(sb.Data) == 0 {
+		return nil, errors.Wrap(ErrInvalidChunk, "empty chunk")
+	}
+	var prev []byte
+	for i, pair := range sb.Data {
+		if i > 0 && bytes.Compare(prev, pair.Key) >= 0 {
+			return nil, errors.Wrap(ErrInvalidChunk, "keys are not sorted")
+		}
+		prev = pair.Key
+	}
+	if !bytes.Equal(sb.Data[0].Key, c.FirstKey) || !bytes.Equal(sb.Data[len(sb.Data)-1].Key, c.LastKey) {
+		return nil, errors.Wrap(ErrInvalidChunk, "key range mismatch")
+	}
+	return sb, nil
+}
@@ -0,0 +1,66 @@
+package snapshot
+
+import (
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestChunkInfo_Decode(t *testing.T) {
+	sb := &Block{}
+	sb.Add([]byte{0x1}, []byte{0x1})
+	sb.Add([]byte{0x2}, []byte{0x2})
+	sb.Add([]byte{0x3}, []byte{0x3})
+	data, _ := rlp.EncodeToBytes(sb)
+	chunk := NewChunkInfo([]byte{0x1}, data, sb)
+
+	decoded, err := chunk.Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, sb, decoded)
+
+	corrupted := append([]byte{}, data...)
+	corrupted[len(corrupted)-1]++
+	_, err = chunk.Decode(corrupted)
+	require.Equal(t, ErrInvalidChunk, errors.Cause(err))
+
+	unsorted := &Block{}
+	unsorted.Add([]byte{0x1}, []byte{0x1})
+	unsorted.Add([]byte{0x3}, []byte{0x3})
+	unsorted.Add([]byte{0x2}, []byte{0x2})
+	data, _ = rlp.EncodeToBytes(unsorted)
+	_, err = NewChunkInfo([]byte{0x1}, data, unsorted).Decode(data)
+	require.Equal(t, ErrInvalidChunk, errors.Cause(err))
+
+	wrongRange := NewChunkInfo([]byte{0x1}, data, sb)
+	data, _ = rlp.EncodeToBytes(sb)
+	wrongRange.Hash = NewChunkInfo(nil, data, sb).Hash
+	wrongRange.LastKey = []byte{0x4}
+	_, err = wrongRange.Decode(data)
+	require.Equal(t, ErrInvalidChunk, errors.Cause(err))
+}
+
+func TestManifestV2_Validate(t *testing.T) {
+	manifest := &ManifestV2{
+		Height: 1,
+		Chunks: []*ChunkInfo{
+			{Cid: []byte{0x1}, FirstKey: []byte{0x1}, LastKey: []byte{0x2}},
+			{Cid: []byte{0x2}, FirstKey: []byte{0x3}, LastKey: []byte{0x5}},
+		},
+	}
+	require.NoError(t, manifest.Validate())
+	require.True(t, manifest.Manifest().IsV2())
+	require.Len(t, manifest.Manifest().Key(), 32)
+
+	manifest.Chunks[1].FirstKey = []byte{0x2}
+	require.Error(t, manifest.Validate())
+
+	manifest.Chunks[1].FirstKey = []byte{0x6}
+	require.Error(t, manifest.Validate())
+
+	require.Error(t, (&ManifestV2{}).Validate())
+
+	v1 := &Manifest{Cid: []byte{0x1}}
+	require.False(t, v1.IsV2())
+	require.Equal(t, []byte{0x1}, v1.Key())
+}
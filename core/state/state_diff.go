@@ -0,0 +1,142 @@
+package state
+
+import (
+	"bytes"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/rlp"
+	"math/big"
+	"sort"
+)
+
+// AccountDiff contains old and new values of the account and identity of the address changed by the block
+type AccountDiff struct {
+	Address    common.Address
+	OldBalance *big.Int
+	NewBalance *big.Int
+	OldNonce   uint32
+	NewNonce   uint32
+	OldStake   *big.Int
+	NewStake   *big.Int
+	OldState   IdentityState
+	NewState   IdentityState
+	OldPenalty *big.Int
+	NewPenalty *big.Int
+}
+
+// StateDiff contains addresses whose balance, nonce, stake, state or penalty are changed by the block,
+// including changes which are not caused by txs
+type StateDiff struct {
+	Accounts []*AccountDiff
+}
+
+func (diff *StateDiff) Empty() bool {
+	return diff == nil || len(diff.Accounts) == 0
+}
+
+func (diff StateDiff) Bytes() []byte {
+	enc, _ := rlp.EncodeToBytes(diff)
+	return enc
+}
+
+// SetTrackChanges turns on keeping committed values of changed accounts and identities which are required by Diff
+func (s *StateDB) SetTrackChanges(enabled bool) {
+	s.trackChanges = enabled
+}
+
+// trackChange saves the committed value of the key before its first change
+func (s *StateDB) trackChange(key []byte) {
+	if !s.trackChanges {
+		return
+	}
+	if s.changes == nil {
+		s.changes = make(map[string][]byte)
+	}
+	if _, ok := s.changes[string(key)]; ok {
+		return
+	}
+	_, value := s.tree.Get(key)
+	s.changes[string(key)] = value
+}
+
+// Diff returns changes of accounts and identities since the last commit, it should be called after Precommit
+func (s *StateDB) Diff() *StateDiff {
+	addresses := make(map[common.Address]struct{})
+	for key := range s.changes {
+		addr := common.Address{}
+		addr.SetBytes([]byte(key)[1:])
+		addresses[addr] = struct{}{}
+	}
+	diff := new(StateDiff)
+	for addr := range addresses {
+		accountKey := append(addressPrefix, addr[:]...)
+		identityKey := append(identityPrefix, addr[:]...)
+
+		oldAccount := s.decodeAccount(s.committedValue(accountKey))
+		oldIdentity := s.decodeIdentity(s.committedValue(identityKey))
+		_, newAccountData := s.tree.Get(accountKey)
+		_, newIdentityData := s.tree.Get(identityKey)
+		newAccount := s.decodeAccount(newAccountData)
+		newIdentity := s.decodeIdentity(newIdentityData)
+
+		item := &AccountDiff{
+			Address:    addr,
+			OldBalance: bigOrZero(oldAccount.Balance),
+			NewBalance: bigOrZero(newAccount.Balance),
+			OldNonce:   oldAccount.Nonce,
+			NewNonce:   newAccount.Nonce,
+			OldStake:   bigOrZero(oldIdentity.Stake),
+			NewStake:   bigOrZero(newIdentity.Stake),
+			OldState:   oldIdentity.State,
+			NewState:   newIdentity.State,
+			OldPenalty: bigOrZero(oldIdentity.Penalty),
+			NewPenalty: bigOrZero(newIdentity.Penalty),
+		}
+		if item.changed() {
+			diff.Accounts = append(diff.Accounts, item)
+		}
+	}
+	sort.Slice(diff.Accounts, func(i, j int) bool {
+		return bytes.Compare(diff.Accounts[i].Address[:], diff.Accounts[j].Address[:]) < 0
+	})
+	return diff
+}
+
+func (s *StateDB) committedValue(key []byte) []byte {
+	if value, ok := s.changes[string(key)]; ok {
+		return value
+	}
+	_, value := s.tree.Get(key)
+	return value
+}
+
+func (s *StateDB) decodeAccount(data []byte) Account {
+	var account Account
+	if len(data) > 0 {
+		if err := rlp.DecodeBytes(data, &account); err != nil {
+			s.log.Error("Failed to decode state account object", "err", err)
+		}
+	}
+	return account
+}
+
+func (s *StateDB) decodeIdentity(data []byte) Identity {
+	var identity Identity
+	if len(data) > 0 {
+		if err := rlp.DecodeBytes(data, &identity); err != nil {
+			s.log.Error("Failed to decode state identity object", "err", err)
+		}
+	}
+	return identity
+}
+
+func (d *AccountDiff) changed() bool {
+	return d.OldBalance.Cmp(d.NewBalance) != 0 || d.OldNonce != d.NewNonce || d.OldStake.Cmp(d.NewStake) != 0 ||
+		d.OldState != d.NewState || d.OldPenalty.Cmp(d.NewPenalty) != 0
+}
+
+func bigOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value
+}
@@ -25,6 +25,28 @@ const (
 	EmptyBlocksBitsSize               = 25
 )
 
+// IdentityStateName returns the name of the identity state used by the RPC and exports
+func IdentityStateName(identityState IdentityState) string {
+	switch identityState {
+	case Invite:
+		return "Invite"
+	case Candidate:
+		return "Candidate"
+	case Newbie:
+		return "Newbie"
+	case Verified:
+		return "Verified"
+	case Suspended:
+		return "Suspended"
+	case Zombie:
+		return "Zombie"
+	case Killed:
+		return "Killed"
+	default:
+		return "Undefined"
+	}
+}
+
 // stateAccount represents an Idena account which is being modified.
 //
 // The usage pattern is as follows:
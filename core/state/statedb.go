@@ -67,6 +67,10 @@ type StateDB struct {
 	stateGlobal      *stateGlobal
 	stateGlobalDirty bool
 
+	// changes keeps committed values of accounts and identities changed since the last commit
+	changes      map[string][]byte
+	trackChanges bool
+
 	log  log.Logger
 	lock sync.Mutex
 }
@@ -147,6 +151,7 @@ func (s *StateDB) Clear() {
 	s.stateIdentitiesDirty = make(map[common.Address]struct{})
 	s.stateGlobal = nil
 	s.stateGlobalDirty = false
+	s.changes = nil
 	s.lock = sync.Mutex{}
 }
 
@@ -450,7 +455,9 @@ func (s *StateDB) updateStateAccountObject(stateObject *stateAccount) {
 		panic(fmt.Errorf("can't encode object at %x: %v", addr[:], err))
 	}
 
-	s.tree.Set(append(addressPrefix, addr[:]...), data)
+	key := append(addressPrefix, addr[:]...)
+	s.trackChange(key)
+	s.tree.Set(key, data)
 }
 
 // updateStateAccountObject writes the given object to the trie.
@@ -461,7 +468,9 @@ func (s *StateDB) updateStateIdentityObject(stateObject *stateIdentity) {
 		panic(fmt.Errorf("can't encode object at %x: %v", addr[:], err))
 	}
 
-	s.tree.Set(append(identityPrefix, addr[:]...), data)
+	key := append(identityPrefix, addr[:]...)
+	s.trackChange(key)
+	s.tree.Set(key, data)
 }
 
 // updateStateAccountObject writes the given object to the trie.
@@ -479,7 +488,9 @@ func (s *StateDB) deleteStateAccountObject(stateObject *stateAccount) {
 	stateObject.deleted = true
 	addr := stateObject.Address()
 
-	s.tree.Remove(append(addressPrefix, addr[:]...))
+	key := append(addressPrefix, addr[:]...)
+	s.trackChange(key)
+	s.tree.Remove(key)
 }
 
 // deleteStateAccountObject removes the given object from the state trie.
@@ -487,7 +498,9 @@ func (s *StateDB) deleteStateIdentityObject(stateObject *stateIdentity) {
 	stateObject.deleted = true
 	addr := stateObject.Address()
 
-	s.tree.Remove(append(identityPrefix, addr[:]...))
+	key := append(identityPrefix, addr[:]...)
+	s.trackChange(key)
+	s.tree.Remove(key)
 }
 
 // Retrieve a state account given my the address. Returns nil if not found.
@@ -928,6 +941,46 @@ func (s *StateDB) WriteSnapshot(height uint64, to io.Writer) (root common.Hash,
 	return tree.WorkingHash(), tar.Close()
 }
 
+// WriteSnapshotChunks splits the state of the given height into chunks of snapshot.BlockSize pairs,
+// every chunk is passed to writeChunk which returns its cid
+func (s *StateDB) WriteSnapshotChunks(height uint64, writeChunk func(data []byte) ([]byte, error)) (root common.Hash, chunks []*snapshot.ChunkInfo, err error) {
+	db := database.NewBackedMemDb(s.db)
+	tree := NewMutableTree(db)
+	if _, err := tree.LoadVersionForOverwriting(int64(height)); err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	it := db.Iterator(nil, nil)
+	defer it.Close()
+
+	writeBlock := func(sb *snapshot.Block) error {
+		data, _ := rlp.EncodeToBytes(sb)
+		cid, err := writeChunk(data)
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, snapshot.NewChunkInfo(cid, data, sb))
+		return nil
+	}
+
+	sb := &snapshot.Block{}
+	for ; it.Valid(); it.Next() {
+		sb.Add(it.Key(), it.Value())
+		if sb.Full() {
+			if err := writeBlock(sb); err != nil {
+				return common.Hash{}, nil, err
+			}
+			sb = &snapshot.Block{}
+		}
+	}
+	if len(sb.Data) > 0 {
+		if err := writeBlock(sb); err != nil {
+			return common.Hash{}, nil, err
+		}
+	}
+	return tree.WorkingHash(), chunks, nil
+}
+
 func clearDb(db dbm.DB) {
 	it := db.Iterator(nil, nil)
 	for ; it.Valid(); it.Next() {
@@ -963,6 +1016,29 @@ func (s *StateDB) RecoverSnapshot(manifest *snapshot.Manifest, from io.Reader) e
 			}
 		}
 	}
+	return validateRecoveredTree(pdb, manifest)
+}
+
+// RecoverSnapshotV2 writes verified chunks of the v2 snapshot, loadChunk returns the chunk by its index in the manifest
+func (s *StateDB) RecoverSnapshotV2(manifest *snapshot.Manifest, loadChunk func(idx int) (*snapshot.Block, error)) error {
+	if err := manifest.V2().Validate(); err != nil {
+		return err
+	}
+	pdb := dbm.NewPrefixDB(s.original, prefix(manifest.Height))
+	for i := range manifest.Chunks {
+		sb, err := loadChunk(i)
+		if err != nil {
+			clearDb(pdb)
+			return err
+		}
+		for _, pair := range sb.Data {
+			pdb.Set(pair.Key, pair.Value)
+		}
+	}
+	return validateRecoveredTree(pdb, manifest)
+}
+
+func validateRecoveredTree(pdb dbm.DB, manifest *snapshot.Manifest) error {
 	tree := NewMutableTree(pdb)
 	if _, err := tree.LoadVersion(int64(manifest.Height)); err != nil {
 		clearDb(pdb)
@@ -6,6 +6,7 @@ import (
 	"github.com/idena-network/idena-go/core/state/snapshot"
 	"github.com/idena-network/idena-go/crypto"
 	"github.com/idena-network/idena-go/database"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tm-db"
 	"math/big"
@@ -347,3 +348,98 @@ func TestStateDB_RecoverSnapshot(t *testing.T) {
 	it := prevStateDb.Iterator(nil, nil)
 	require.False(t, it.Valid())
 }
+
+func TestStateDB_RecoverSnapshotV2(t *testing.T) {
+	database := db.NewMemDB()
+	stateDb := NewLazy(database)
+
+	const AddrsCount = 25000
+	const Height = uint64(1)
+	for i := 0; i < AddrsCount; i++ {
+		addr := common.Address{}
+		addr.SetBytes(common.ToBytes(uint64(i)))
+		stateDb.SetNonce(addr, uint32(i+1))
+	}
+	stateDb.Commit(true)
+	expectedRoot := stateDb.Root()
+
+	stateDb.AddInvite(common.Address{}, 2)
+	stateDb.Commit(true)
+
+	stored := make(map[string][]byte)
+	root, chunks, err := stateDb.WriteSnapshotChunks(Height, func(data []byte) ([]byte, error) {
+		cid := crypto.Keccak256(data)
+		stored[string(cid)] = data
+		return cid, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, root)
+	require.True(t, len(chunks) > 1)
+
+	manifest := &snapshot.Manifest{
+		Height: Height,
+		Root:   root,
+		Chunks: chunks,
+	}
+	loadChunk := func(idx int) (*snapshot.Block, error) {
+		return chunks[idx].Decode(stored[string(chunks[idx].Cid)])
+	}
+
+	corrupted := append([]byte{}, stored[string(chunks[1].Cid)]...)
+	corrupted[len(corrupted)-1]++
+	_, err = chunks[1].Decode(corrupted)
+	require.Equal(t, snapshot.ErrInvalidChunk, errors.Cause(err))
+
+	require.NoError(t, stateDb.RecoverSnapshotV2(manifest, loadChunk))
+	stateDb.CommitSnapshot(manifest)
+
+	require.Equal(t, int64(Height), stateDb.tree.Version())
+	require.Equal(t, expectedRoot, stateDb.Root())
+	cnt := 0
+	stateDb.IterateAccounts(func(key []byte, value []byte) bool {
+		cnt++
+		return false
+	})
+	require.Equal(t, AddrsCount, cnt)
+}
+
+func TestStateDB_Diff(t *testing.T) {
+	database := db.NewMemDB()
+	stateDb := NewLazy(database)
+	stateDb.SetTrackChanges(true)
+
+	addr1, addr2, addr3 := common.Address{0x1}, common.Address{0x2}, common.Address{0x3}
+	stateDb.SetBalance(addr1, big.NewInt(10))
+	stateDb.SetState(addr2, Verified)
+	stateDb.AddStake(addr2, big.NewInt(5))
+	stateDb.Commit(true)
+
+	stateDb.SetBalance(addr1, big.NewInt(15))
+	stateDb.SetNonce(addr1, 1)
+	stateDb.SetState(addr2, Suspended)
+	stateDb.SetPenalty(addr2, big.NewInt(3))
+	stateDb.SetBalance(addr3, big.NewInt(1))
+	stateDb.SetBalance(addr3, big.NewInt(0))
+	stateDb.AddInvite(addr1, 1)
+	stateDb.Precommit(true)
+
+	require := require.New(t)
+	diff := stateDb.Diff()
+	require.Len(diff.Accounts, 2)
+
+	require.Equal(addr1, diff.Accounts[0].Address)
+	require.Equal(big.NewInt(10), diff.Accounts[0].OldBalance)
+	require.Equal(big.NewInt(15), diff.Accounts[0].NewBalance)
+	require.Equal(uint32(0), diff.Accounts[0].OldNonce)
+	require.Equal(uint32(1), diff.Accounts[0].NewNonce)
+
+	require.Equal(addr2, diff.Accounts[1].Address)
+	require.Equal(Verified, diff.Accounts[1].OldState)
+	require.Equal(Suspended, diff.Accounts[1].NewState)
+	require.Equal(big.NewInt(5), diff.Accounts[1].NewStake)
+	require.Equal(0, diff.Accounts[1].OldPenalty.Sign())
+	require.Equal(big.NewInt(3), diff.Accounts[1].NewPenalty)
+
+	stateDb.Commit(true)
+	require.True(stateDb.Diff().Empty())
+}
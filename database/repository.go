@@ -6,8 +6,10 @@ import (
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/math"
+	"github.com/idena-network/idena-go/core/state/snapshot"
 	"github.com/idena-network/idena-go/log"
 	"github.com/idena-network/idena-go/rlp"
+	statsTypes "github.com/idena-network/idena-go/stats/types"
 	dbm "github.com/tendermint/tm-db"
 	"math/big"
 	"sort"
@@ -87,6 +89,33 @@ func identityStateDiffKey(height uint64) []byte {
 	return append(identityStateDiffPrefix, encodeUint64Number(height)...)
 }
 
+func stateDiffKey(height uint64) []byte {
+	return append(stateDiffPrefix, encodeUint64Number(height)...)
+}
+
+func offlinePenaltyKey(height uint64, addr common.Address) []byte {
+	key := append(offlinePenaltyPrefix, encodeUint64Number(height)...)
+	return append(key, addr[:]...)
+}
+
+func validationResultKey(epoch uint16, addr common.Address) []byte {
+	key := append(validationResultPrefix, encodeUint16Number(epoch)...)
+	return append(key, addr[:]...)
+}
+
+func blockStatsKey(height uint64) []byte {
+	return append(blockStatsPrefix, encodeUint64Number(height)...)
+}
+
+func addressStatsKey(height uint64, addr common.Address) []byte {
+	key := append(addressStatsPrefix, encodeUint64Number(height)...)
+	return append(key, addr[:]...)
+}
+
+func inviteKey(receiver common.Address) []byte {
+	return append(invitePrefix, receiver[:]...)
+}
+
 func (r *Repo) ReadBlockHeader(hash common.Hash) *types.Header {
 	data := r.db.Get(headerKey(hash))
 	if data == nil {
@@ -294,6 +323,29 @@ func (r *Repo) WriteLastSnapshotManifest(cid []byte, root common.Hash, height ui
 	return nil
 }
 
+func (r *Repo) LastSnapshotManifestV2() *snapshot.ManifestV2 {
+	data := r.db.Get(lastSnapshotV2Key)
+	if data == nil {
+		return nil
+	}
+	manifest := new(snapshot.ManifestV2)
+	if err := rlp.DecodeBytes(data, manifest); err != nil {
+		log.Error("invalid snapshot manifest v2 RLP", "err", err)
+		return nil
+	}
+	return manifest
+}
+
+func (r *Repo) WriteLastSnapshotManifestV2(manifest *snapshot.ManifestV2) error {
+	data, err := rlp.EncodeToBytes(manifest)
+	if err != nil {
+		log.Crit("failed to RLP encode snapshot manifest v2", "err", err)
+		return err
+	}
+	r.db.Set(lastSnapshotV2Key, data)
+	return nil
+}
+
 func (r *Repo) WriteIdentityStateDiff(height uint64, diff []byte) {
 	r.db.Set(identityStateDiffKey(height), diff)
 }
@@ -302,6 +354,31 @@ func (r *Repo) ReadIdentityStateDiff(height uint64) []byte {
 	return r.db.Get(identityStateDiffKey(height))
 }
 
+func (r *Repo) WriteStateDiff(height uint64, diff []byte) {
+	r.db.Set(stateDiffKey(height), diff)
+}
+
+func (r *Repo) ReadStateDiff(height uint64) []byte {
+	return r.db.Get(stateDiffKey(height))
+}
+
+func (r *Repo) DeleteStateDiff(height uint64) {
+	r.db.Delete(stateDiffKey(height))
+}
+
+// DeleteStateDiffs removes diffs of all blocks starting from the height
+func (r *Repo) DeleteStateDiffs(fromHeight uint64) {
+	it := r.db.Iterator(stateDiffKey(fromHeight), stateDiffKey(math.MaxUint64))
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	it.Close()
+	for _, key := range keys {
+		r.db.Delete(key)
+	}
+}
+
 func (r *Repo) WritePreliminaryHead(header *types.Header) {
 	data, err := rlp.EncodeToBytes(header)
 	if err != nil {
@@ -328,6 +405,37 @@ func (r *Repo) RemovePreliminaryHead() {
 	r.db.Delete(preliminaryHeadKey)
 }
 
+type genesisDb struct {
+	Height  uint64
+	Network types.Network
+}
+
+func (r *Repo) WriteGenesis(height uint64, network types.Network) {
+	data, err := rlp.EncodeToBytes(&genesisDb{
+		Height:  height,
+		Network: network,
+	})
+	if err != nil {
+		log.Crit("failed to RLP encode genesis", "err", err)
+		return
+	}
+	r.db.Set(genesisKey, data)
+}
+
+// ReadGenesis returns false if the chain was created before the genesis record was introduced
+func (r *Repo) ReadGenesis() (height uint64, network types.Network, ok bool) {
+	data := r.db.Get(genesisKey)
+	if data == nil {
+		return 0, 0, false
+	}
+	genesis := new(genesisDb)
+	if err := rlp.DecodeBytes(data, genesis); err != nil {
+		log.Error("invalid genesis RLP", "err", err)
+		return 0, 0, false
+	}
+	return genesis.Height, genesis.Network, true
+}
+
 type activityMonitorDb struct {
 	UpdateDt uint64
 	Data     []*addrActivityDb
@@ -484,3 +592,211 @@ func (r *Repo) GetTotalBurntCoins() []*types.BurntCoins {
 
 	return res
 }
+
+func (r *Repo) WriteOfflinePenalty(penalty *types.OfflinePenalty) {
+	data, err := rlp.EncodeToBytes(penalty)
+	if err != nil {
+		log.Crit("failed to RLP encode offline penalty", "err", err)
+		return
+	}
+	r.db.Set(offlinePenaltyKey(penalty.Height, penalty.Address), data)
+}
+
+// GetOfflinePenalties returns applied offline penalties ordered by height desc, all addresses are included if address is nil
+func (r *Repo) GetOfflinePenalties(address *common.Address) []*types.OfflinePenalty {
+	it := r.db.ReverseIterator(offlinePenaltyKey(0, common.Address{}), offlinePenaltyKey(math.MaxUint64, common.BytesToAddress(common.MaxAddr)))
+	defer it.Close()
+
+	var res []*types.OfflinePenalty
+	for ; it.Valid(); it.Next() {
+		key, value := it.Key(), it.Value()
+		penalty := new(types.OfflinePenalty)
+		if err := rlp.DecodeBytes(value, penalty); err != nil {
+			log.Error("cannot parse offline penalty", "key", key)
+			continue
+		}
+		if address != nil && penalty.Address != *address {
+			continue
+		}
+		res = append(res, penalty)
+	}
+	return res
+}
+
+func (r *Repo) DeleteOfflinePenalties(fromHeight uint64) {
+	it := r.db.Iterator(offlinePenaltyKey(fromHeight, common.Address{}), offlinePenaltyKey(math.MaxUint64, common.BytesToAddress(common.MaxAddr)))
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	it.Close()
+	for _, key := range keys {
+		r.db.Delete(key)
+	}
+}
+
+func (r *Repo) WriteValidationResults(results []*types.IdentityValidationResult) {
+	batch := r.db.NewBatch()
+	defer batch.Close()
+	for _, result := range results {
+		data, err := rlp.EncodeToBytes(result)
+		if err != nil {
+			log.Crit("failed to RLP encode validation result", "err", err)
+			return
+		}
+		batch.Set(validationResultKey(result.Epoch, result.Address), data)
+	}
+	batch.Write()
+}
+
+func (r *Repo) ReadValidationResult(epoch uint16, addr common.Address) *types.IdentityValidationResult {
+	data := r.db.Get(validationResultKey(epoch, addr))
+	if data == nil {
+		return nil
+	}
+	result := new(types.IdentityValidationResult)
+	if err := rlp.DecodeBytes(data, result); err != nil {
+		log.Error("invalid validation result RLP", "err", err)
+		return nil
+	}
+	return result
+}
+
+func (r *Repo) WriteBlockStats(stats *statsTypes.BlockStats, addresses []*statsTypes.AddressStats) {
+	batch := r.db.NewBatch()
+	defer batch.Close()
+	data, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		log.Crit("failed to RLP encode block stats", "err", err)
+		return
+	}
+	batch.Set(blockStatsKey(stats.Height), data)
+	for _, item := range addresses {
+		data, err := rlp.EncodeToBytes(item)
+		if err != nil {
+			log.Crit("failed to RLP encode address stats", "err", err)
+			return
+		}
+		batch.Set(addressStatsKey(item.Height, item.Address), data)
+	}
+	batch.Write()
+}
+
+// GetBlockStats returns stats of all stored blocks ordered by height asc
+func (r *Repo) GetBlockStats() []*statsTypes.BlockStats {
+	it := r.db.Iterator(blockStatsKey(0), blockStatsKey(math.MaxUint64))
+	defer it.Close()
+
+	var res []*statsTypes.BlockStats
+	for ; it.Valid(); it.Next() {
+		stats := new(statsTypes.BlockStats)
+		if err := rlp.DecodeBytes(it.Value(), stats); err != nil {
+			log.Error("cannot parse block stats", "key", it.Key())
+			continue
+		}
+		res = append(res, stats)
+	}
+	return res
+}
+
+func (r *Repo) ReadAddressStats(height uint64, addr common.Address) *statsTypes.AddressStats {
+	data := r.db.Get(addressStatsKey(height, addr))
+	if data == nil {
+		return nil
+	}
+	stats := new(statsTypes.AddressStats)
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		log.Error("invalid address stats RLP", "err", err)
+		return nil
+	}
+	return stats
+}
+
+// GetAddressStats returns stats of all addresses of the block
+func (r *Repo) GetAddressStats(height uint64) []*statsTypes.AddressStats {
+	it := r.db.Iterator(addressStatsKey(height, common.Address{}), addressStatsKey(height+1, common.Address{}))
+	defer it.Close()
+
+	var res []*statsTypes.AddressStats
+	for ; it.Valid(); it.Next() {
+		stats := new(statsTypes.AddressStats)
+		if err := rlp.DecodeBytes(it.Value(), stats); err != nil {
+			log.Error("cannot parse address stats", "key", it.Key())
+			continue
+		}
+		res = append(res, stats)
+	}
+	return res
+}
+
+func (r *Repo) DeleteBlockStats(height uint64) {
+	it := r.db.Iterator(addressStatsKey(height, common.Address{}), addressStatsKey(height+1, common.Address{}))
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	it.Close()
+	batch := r.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		batch.Delete(key)
+	}
+	batch.Delete(blockStatsKey(height))
+	batch.Write()
+}
+
+// DeleteBlockStatsFrom removes stats of all blocks starting from the height
+func (r *Repo) DeleteBlockStatsFrom(fromHeight uint64) {
+	it := r.db.Iterator(blockStatsKey(fromHeight), blockStatsKey(math.MaxUint64))
+	var heights []uint64
+	for ; it.Valid(); it.Next() {
+		stats := new(statsTypes.BlockStats)
+		if err := rlp.DecodeBytes(it.Value(), stats); err != nil {
+			log.Error("cannot parse block stats", "key", it.Key())
+			continue
+		}
+		heights = append(heights, stats.Height)
+	}
+	it.Close()
+	for _, height := range heights {
+		r.DeleteBlockStats(height)
+	}
+}
+
+func (r *Repo) WriteInvite(invite *types.SavedInvite) {
+	data, err := rlp.EncodeToBytes(invite)
+	if err != nil {
+		log.Crit("failed to RLP encode invite", "err", err)
+		return
+	}
+	r.db.Set(inviteKey(invite.Receiver), data)
+}
+
+func (r *Repo) ReadInvite(receiver common.Address) *types.SavedInvite {
+	data := r.db.Get(inviteKey(receiver))
+	if data == nil {
+		return nil
+	}
+	invite := new(types.SavedInvite)
+	if err := rlp.DecodeBytes(data, invite); err != nil {
+		log.Error("invalid invite RLP", "err", err)
+		return nil
+	}
+	return invite
+}
+
+func (r *Repo) GetInvites() []*types.SavedInvite {
+	it := r.db.Iterator(inviteKey(common.Address{}), inviteKey(common.BytesToAddress(common.MaxAddr)))
+	defer it.Close()
+
+	var res []*types.SavedInvite
+	for ; it.Valid(); it.Next() {
+		invite := new(types.SavedInvite)
+		if err := rlp.DecodeBytes(it.Value(), invite); err != nil {
+			log.Error("cannot parse invite", "key", it.Key())
+			continue
+		}
+		res = append(res, invite)
+	}
+	return res
+}
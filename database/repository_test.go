@@ -4,8 +4,10 @@ import (
 	"crypto/rand"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
+	statsTypes "github.com/idena-network/idena-go/stats/types"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tm-db"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -73,3 +75,135 @@ func TestRepo_WritActivityMonitor(t *testing.T) {
 	require.Equal(monitor.Data[0].Addr, readActivity.Data[0].Addr)
 	require.Equal(monitor.Data[0].Time.Unix(), readActivity.Data[0].Time.Unix())
 }
+
+func TestRepo_WriteValidationResults(t *testing.T) {
+	database := db.NewMemDB()
+	repo := NewRepo(database)
+
+	addr1, addr2 := common.Address{0x1}, common.Address{0x2}
+	repo.WriteValidationResults([]*types.IdentityValidationResult{
+		{
+			Address:     addr1,
+			Epoch:       5,
+			PrevState:   3,
+			NewState:    3,
+			Candidate:   true,
+			Approved:    true,
+			ShortPoints: 9,
+			ShortFlips:  5,
+			DisqualifiedFlips: []*types.DisqualifiedFlip{
+				{Cid: []byte{0x1, 0x2}, Short: true, Status: 3, Reason: "reason"},
+			},
+			Reason: "rule",
+		},
+		{
+			Address: addr2,
+			Epoch:   5,
+			Missed:  true,
+		},
+	})
+
+	require := require.New(t)
+
+	result := repo.ReadValidationResult(5, addr1)
+	require.NotNil(result)
+	require.Equal(uint32(9), result.ShortPoints)
+	require.Equal("rule", result.Reason)
+	require.Len(result.DisqualifiedFlips, 1)
+	require.Equal([]byte{0x1, 0x2}, result.DisqualifiedFlips[0].Cid)
+	require.True(result.DisqualifiedFlips[0].Short)
+
+	require.True(repo.ReadValidationResult(5, addr2).Missed)
+	require.Nil(repo.ReadValidationResult(4, addr1))
+}
+
+func TestRepo_WriteInvite(t *testing.T) {
+	database := db.NewMemDB()
+	repo := NewRepo(database)
+
+	addr1, addr2 := common.Address{0x1}, common.Address{0x2}
+	repo.WriteInvite(&types.SavedInvite{
+		Receiver:     addr1,
+		TxHash:       common.Hash{0x1},
+		Epoch:        3,
+		EncryptedKey: []byte{0x1, 0x2},
+	})
+	repo.WriteInvite(&types.SavedInvite{
+		Receiver: addr2,
+		TxHash:   common.Hash{0x2},
+		Epoch:    3,
+	})
+
+	require := require.New(t)
+
+	invite := repo.ReadInvite(addr1)
+	require.NotNil(invite)
+	require.Equal(common.Hash{0x1}, invite.TxHash)
+	require.Equal([]byte{0x1, 0x2}, invite.EncryptedKey)
+	require.Nil(repo.ReadInvite(common.Address{0x3}))
+
+	invite.ActivatedAddress = common.Address{0x4}
+	repo.WriteInvite(invite)
+	require.Equal(common.Address{0x4}, repo.ReadInvite(addr1).ActivatedAddress)
+
+	require.Len(repo.GetInvites(), 2)
+}
+
+func TestRepo_DeleteStateDiffs(t *testing.T) {
+	repo := NewRepo(db.NewMemDB())
+	for h := uint64(1); h <= 5; h++ {
+		repo.WriteStateDiff(h, []byte{byte(h)})
+	}
+
+	repo.DeleteStateDiff(1)
+	repo.DeleteStateDiffs(4)
+
+	require.Nil(t, repo.ReadStateDiff(1))
+	require.Equal(t, []byte{0x2}, repo.ReadStateDiff(2))
+	require.Equal(t, []byte{0x3}, repo.ReadStateDiff(3))
+	require.Nil(t, repo.ReadStateDiff(4))
+	require.Nil(t, repo.ReadStateDiff(5))
+}
+
+func TestRepo_DeleteBlockStatsFrom(t *testing.T) {
+	require := require.New(t)
+	repo := NewRepo(db.NewMemDB())
+	addr := common.Address{0x1}
+	for h := uint64(10); h <= 30; h += 10 {
+		repo.WriteBlockStats(&statsTypes.BlockStats{Height: h, Epoch: uint16(h / 10)}, []*statsTypes.AddressStats{
+			{Address: addr, Height: h, Epoch: uint16(h / 10)},
+		})
+	}
+
+	repo.DeleteBlockStatsFrom(20)
+
+	blockStats := repo.GetBlockStats()
+	require.Len(blockStats, 1)
+	require.Equal(uint64(10), blockStats[0].Height)
+	require.NotNil(repo.ReadAddressStats(10, addr))
+	require.Nil(repo.ReadAddressStats(20, addr))
+	require.Nil(repo.ReadAddressStats(30, addr))
+}
+
+func TestRepo_OfflinePenalties(t *testing.T) {
+	require := require.New(t)
+	repo := NewRepo(db.NewMemDB())
+
+	addr1, addr2 := common.Address{0x1}, common.Address{0x2}
+	penalties := []*types.OfflinePenalty{
+		{Address: addr1, BlockHash: common.Hash{0x1}, Height: 10, Timestamp: 100, Penalty: big.NewInt(1)},
+		{Address: addr2, BlockHash: common.Hash{0x2}, Height: 20, Timestamp: 200, Penalty: big.NewInt(2)},
+		{Address: addr1, BlockHash: common.Hash{0x3}, Height: 30, Timestamp: 300, Penalty: big.NewInt(3)},
+	}
+	for _, penalty := range penalties {
+		repo.WriteOfflinePenalty(penalty)
+	}
+
+	require.Equal([]*types.OfflinePenalty{penalties[2], penalties[1], penalties[0]}, repo.GetOfflinePenalties(nil))
+	require.Equal([]*types.OfflinePenalty{penalties[2], penalties[0]}, repo.GetOfflinePenalties(&addr1))
+	require.Empty(repo.GetOfflinePenalties(&common.Address{0x3}))
+
+	repo.DeleteOfflinePenalties(20)
+	require.Equal([]*types.OfflinePenalty{penalties[0]}, repo.GetOfflinePenalties(nil))
+	require.Empty(repo.GetOfflinePenalties(&addr2))
+}
@@ -26,9 +26,25 @@ var (
 
 	lastSnapshotKey = []byte("last-snapshot")
 
+	lastSnapshotV2Key = []byte("last-snapshot-v2")
+
 	identityStateDiffPrefix = []byte("id-diff")
 
+	stateDiffPrefix = []byte("sd") // stateDiffPrefix + num (uint64 big endian) -> account changes of the block
+
 	preliminaryHeadKey = []byte("preliminary-head")
 
+	genesisKey = []byte("genesis") // -> height of the genesis block and the network it was created for
+
 	activityMonitorKey = []byte("activity")
+
+	offlinePenaltyPrefix = []byte("op") // offlinePenaltyPrefix + num (uint64 big endian) + address -> penalty
+
+	validationResultPrefix = []byte("vr") // validationResultPrefix + epoch (uint16 big endian) + address -> validation result
+
+	blockStatsPrefix = []byte("sb") // blockStatsPrefix + num (uint64 big endian) -> block stats
+
+	addressStatsPrefix = []byte("sa") // addressStatsPrefix + num (uint64 big endian) + address -> address stats
+
+	invitePrefix = []byte("inv") // invitePrefix + receiver address -> saved invite
 )
@@ -2,6 +2,7 @@ package events
 
 import (
 	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/eventbus"
 )
 
@@ -11,6 +12,24 @@ const (
 	NewFlipKeyID      = eventbus.EventID("flip-key-new")
 	FastSyncCompleted = eventbus.EventID("fast-sync-completed")
 	NewFlipEventID    = eventbus.EventID("flip-new")
+	TxReplacedEventID = eventbus.EventID("transaction-replaced")
+	TxDroppedEventID  = eventbus.EventID("transaction-dropped")
+
+	CeremonyProgressEventID = eventbus.EventID("ceremony-progress")
+)
+
+const (
+	CeremonyCandidatesCalculated = "candidates-calculated"
+	CeremonyFlipKeyBroadcast     = "flip-key-broadcast"
+	CeremonyShortFlipsLoaded     = "short-flips-loaded"
+	CeremonyLongFlipsLoaded      = "long-flips-loaded"
+	CeremonyFlipsReady           = "flips-ready"
+	CeremonyShortAnswersHashSent = "short-answers-hash-sent"
+	CeremonyShortAnswersRevealed = "short-answers-revealed"
+	CeremonyLongAnswersSent      = "long-answers-sent"
+	CeremonyEvidenceMapSent      = "evidence-map-sent"
+	CeremonyEpochApplied         = "epoch-applied"
+	CeremonyError                = "error"
 )
 
 type NewTxEvent struct {
@@ -53,3 +72,36 @@ type NewFlipEvent struct {
 func (NewFlipEvent) EventID() eventbus.EventID {
 	return NewFlipEventID
 }
+
+type TxReplacedEvent struct {
+	Old *types.Transaction
+	New *types.Transaction
+}
+
+func (e *TxReplacedEvent) EventID() eventbus.EventID {
+	return TxReplacedEventID
+}
+
+type TxDroppedEvent struct {
+	Tx     *types.Transaction
+	Reason string
+	Own    bool
+}
+
+func (e *TxDroppedEvent) EventID() eventbus.EventID {
+	return TxDroppedEventID
+}
+
+// CeremonyProgressEvent is published by the validation ceremony when the node passes a ceremony milestone
+type CeremonyProgressEvent struct {
+	Epoch  uint16
+	Stage  string
+	Count  int
+	TxType uint16
+	TxHash common.Hash
+	Error  string
+}
+
+func (e *CeremonyProgressEvent) EventID() eventbus.EventID {
+	return CeremonyProgressEventID
+}
@@ -32,6 +32,8 @@ func main() {
 		config.TcpPortFlag,
 		config.RpcHostFlag,
 		config.RpcPortFlag,
+		config.WsHostFlag,
+		config.WsPortFlag,
 		config.BootNodeFlag,
 		config.AutomineFlag,
 		config.IpfsBootNodeFlag,
@@ -43,9 +45,15 @@ func main() {
 		config.MaxNetworkDelayFlag,
 		config.FastSyncFlag,
 		config.ForceFullSyncFlag,
+		config.SnapshotV2Flag,
 		config.ProfileFlag,
 		config.IpfsPortStaticFlag,
 		config.ApiKeyFlag,
+		config.ArchiveFlag,
+		config.StatsFlag,
+		config.StateDiffFlag,
+		config.SignerFlag,
+		config.NetworkFlag,
 	}
 
 	app.Action = func(context *cli.Context) error {
@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"fmt"
+	"github.com/awnumar/memguard"
 	"github.com/idena-network/idena-go/api"
 	"github.com/idena-network/idena-go/blockchain"
-	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/common/eventbus"
 	util "github.com/idena-network/idena-go/common/ulimit"
 	"github.com/idena-network/idena-go/config"
@@ -14,6 +14,7 @@ import (
 	"github.com/idena-network/idena-go/core/appstate"
 	"github.com/idena-network/idena-go/core/ceremony"
 	"github.com/idena-network/idena-go/core/flip"
+	"github.com/idena-network/idena-go/core/invites"
 	"github.com/idena-network/idena-go/core/mempool"
 	"github.com/idena-network/idena-go/core/profile"
 	"github.com/idena-network/idena-go/core/state"
@@ -24,7 +25,6 @@ import (
 	"github.com/idena-network/idena-go/p2p"
 	"github.com/idena-network/idena-go/pengings"
 	"github.com/idena-network/idena-go/protocol"
-	"github.com/idena-network/idena-go/rlp"
 	"github.com/idena-network/idena-go/rpc"
 	"github.com/idena-network/idena-go/secstore"
 	"github.com/idena-network/idena-go/stats/collector"
@@ -34,18 +34,13 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/tendermint/tm-db"
 )
 
-const (
-	// todo set real values
-	totalTxLimit = 100000
-	addrTxLimit  = 1000
-)
-
 type Node struct {
 	config          *config.Config
 	blockchain      *blockchain.Blockchain
@@ -61,6 +56,8 @@ type Node struct {
 	rpcAPIs         []rpc.API
 	httpListener    net.Listener // HTTP RPC listener socket to server API requests
 	httpHandler     *rpc.Server  // HTTP RPC request handler to process the API requests
+	wsListener      net.Listener // Websocket RPC listener socket to server API requests
+	wsHandler       *rpc.Server  // Websocket RPC request handler to process the API requests
 	log             log.Logger
 	srv             *p2p.Server
 	keyStore        *keystore.KeyStore
@@ -72,6 +69,9 @@ type Node struct {
 	offlineDetector *blockchain.OfflineDetector
 	appVersion      string
 	profileManager  *profile.Manager
+	ownTxWatcher    *mempool.OwnTxWatcher
+	invites         *invites.Manager
+	archiver        *ceremony.Archiver
 }
 
 type NodeCtx struct {
@@ -121,7 +121,7 @@ func ProvideMobileKey(path string, cfg string, key string, password string) stri
 }
 
 func NewNode(config *config.Config, appVersion string) (*Node, error) {
-	nodeCtx, err := NewNodeWithInjections(config, eventbus.New(), collector.NewBlockStatsCollector(), appVersion)
+	nodeCtx, err := NewNodeWithInjections(config, eventbus.New(), nil, appVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +136,13 @@ func NewNodeWithInjections(config *config.Config, bus eventbus.Bus, blockStatsCo
 		return nil, err
 	}
 
+	if err := config.CheckDataDirNetwork(func(candidates []uint32) (uint32, bool, error) {
+		return blockchain.ReadChainNetwork(db, candidates)
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	keyStoreDir, err := config.KeyStoreDataDir()
 	if err != nil {
 		return nil, err
@@ -155,11 +162,20 @@ func NewNodeWithInjections(config *config.Config, bus eventbus.Bus, blockStatsCo
 	secStore := secstore.NewSecStore()
 	appState := appstate.NewAppState(db, bus)
 
+	if blockStatsCollector == nil {
+		if config.Stats.Enabled {
+			blockStatsCollector = collector.NewPersistentBlockStatsCollector(config.Stats, db, bus, appState)
+		} else {
+			blockStatsCollector = collector.NewBlockStatsCollector()
+		}
+	}
+
 	offlineDetector := blockchain.NewOfflineDetector(config.OfflineDetection, db, appState, secStore, bus)
 	votes := pengings.NewVotes(appState, bus, offlineDetector)
 
-	txpool := mempool.NewTxPool(appState, bus, totalTxLimit, addrTxLimit, config.Consensus.MinFeePerByte)
+	txpool := mempool.NewTxPool(appState, bus, config.Mempool, config.Consensus.MinFeePerByte)
 	flipKeyPool := mempool.NewKeysPool(appState, bus)
+	ownTxWatcher := mempool.NewOwnTxWatcher(config.Mempool, bus, txpool, appState, secStore)
 
 	chain := blockchain.NewBlockchain(config, db, txpool, appState, ipfsProxy, secStore, bus, offlineDetector, blockStatsCollector)
 	proposals, proofsByRound, pendingProofs := pengings.NewProposals(chain, offlineDetector)
@@ -168,8 +184,10 @@ func NewNodeWithInjections(config *config.Config, bus eventbus.Bus, blockStatsCo
 	sm := state.NewSnapshotManager(db, appState.State, bus, ipfsProxy, config)
 	downloader := protocol.NewDownloader(pm, config, chain, ipfsProxy, appState, sm, bus, secStore)
 	consensusEngine := consensus.NewEngine(chain, pm, proposals, config.Consensus, appState, votes, txpool, secStore, downloader, offlineDetector)
-	ceremony := ceremony.NewValidationCeremony(appState, bus, flipper, secStore, db, txpool, chain, downloader, flipKeyPool, config)
+	archiver := ceremony.NewArchiver(config.ArchiveDir())
+	ceremony := ceremony.NewValidationCeremony(appState, bus, flipper, secStore, db, txpool, chain, downloader, flipKeyPool, config, archiver)
 	profileManager := profile.NewProfileManager(ipfsProxy)
+	invitesManager := invites.NewManager(db, appState, secStore, bus)
 	node := &Node{
 		config:          config,
 		blockchain:      chain,
@@ -191,7 +209,14 @@ func NewNodeWithInjections(config *config.Config, bus eventbus.Bus, blockStatsCo
 		votes:           votes,
 		appVersion:      appVersion,
 		profileManager:  profileManager,
+		ownTxWatcher:    ownTxWatcher,
+		invites:         invitesManager,
+		archiver:        archiver,
 	}
+	memguard.CatchSignal(func(signal os.Signal) {
+		fmt.Printf("%v signal received. Exiting...\n", signal)
+		node.Stop()
+	}, os.Interrupt, syscall.SIGTERM)
 	return &NodeCtx{
 		Node:            node,
 		AppState:        appState,
@@ -210,7 +235,16 @@ func (node *Node) Start() {
 }
 
 func (node *Node) StartWithHeight(height uint64) {
-	node.secStore.AddKey(crypto.FromECDSA(node.config.NodeKey()))
+	if node.config.Signer.Socket != "" {
+		signer, err := secstore.NewRemoteSigner(node.config.Signer.Socket, node.config.Signer.Timeout)
+		if err != nil {
+			node.log.Crit("Cannot connect to the external signer", "err", err)
+		}
+		node.secStore.SetSigner(signer)
+		node.log.Info("External signer is used", "address", signer.Address().Hex())
+	} else {
+		node.secStore.AddKey(crypto.FromECDSA(node.config.NodeKey()))
+	}
 
 	cfg := node.config.P2P
 	cfg.Protocols = []p2p.Protocol{
@@ -253,7 +287,9 @@ func (node *Node) StartWithHeight(height uint64) {
 		}
 	}
 
-	node.txpool.Initialize(node.blockchain.Head, node.secStore.GetAddress())
+	node.ownTxWatcher.Start()
+	node.invites.Start()
+	node.txpool.Initialize(node.blockchain.Head, node.secStore.GetAddress(), node.config.MempoolJournal())
 	node.flipKeyPool.Initialize(node.blockchain.Head)
 	node.votes.Initialize(node.blockchain.Head)
 	node.fp.Initialize()
@@ -274,6 +310,15 @@ func (node *Node) StartWithHeight(height uint64) {
 	}
 }
 
+// Stop flushes the state which should survive the restart and destroys the node key, the process is expected to exit after it
+func (node *Node) Stop() {
+	node.stopWS()
+	node.stopHTTP()
+	node.ownTxWatcher.Stop()
+	node.txpool.Stop()
+	node.secStore.Destroy()
+}
+
 func (node *Node) WaitForStop() {
 	<-node.stop
 	node.secStore.Destroy()
@@ -295,6 +340,10 @@ func (node *Node) startRPC() error {
 	if err := node.startHTTP(node.config.RPC.HTTPEndpoint(), apis, node.config.RPC.HTTPModules, node.config.RPC.HTTPCors, node.config.RPC.HTTPVirtualHosts, node.config.RPC.HTTPTimeouts, apiKey); err != nil {
 		return err
 	}
+	if err := node.startWS(node.config.RPC.WSEndpoint(), apis, node.config.RPC.WSModules, node.config.RPC.WSOrigins, apiKey); err != nil {
+		node.stopHTTP()
+		return err
+	}
 
 	node.rpcAPIs = apis
 	return nil
@@ -332,6 +381,38 @@ func (node *Node) stopHTTP() {
 	}
 }
 
+// startWS initializes and starts the websocket RPC endpoint.
+func (node *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrigins []string, apiKey string) error {
+	// Short circuit if the WS endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, false, apiKey)
+	if err != nil {
+		return err
+	}
+	node.log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()))
+
+	node.wsListener = listener
+	node.wsHandler = handler
+
+	return nil
+}
+
+// stopWS terminates the websocket RPC endpoint.
+func (node *Node) stopWS() {
+	if node.wsListener != nil {
+		node.wsListener.Close()
+		node.wsListener = nil
+
+		node.log.Info("WebSocket endpoint closed", "url", fmt.Sprintf("ws://%s", node.config.RPC.WSEndpoint()))
+	}
+	if node.wsHandler != nil {
+		node.wsHandler.Stop()
+		node.wsHandler = nil
+	}
+}
+
 func OpenDatabase(datadir string, name string, cache int, handles int) (db.DB, error) {
 	return db.NewGoLevelDBWithOpts(name, datadir, &opt.Options{
 		OpenFilesCacheCapacity: handles,
@@ -356,7 +437,7 @@ func (node *Node) apis() []rpc.API {
 		{
 			Namespace: "dna",
 			Version:   "1.0",
-			Service:   api.NewDnaApi(baseApi, node.blockchain, node.ceremony, node.appVersion, node.profileManager),
+			Service:   api.NewDnaApi(baseApi, node.blockchain, node.ceremony, node.appVersion, node.profileManager, node.offlineDetector, node.bus, node.invites),
 			Public:    true,
 		},
 		{
@@ -368,7 +449,7 @@ func (node *Node) apis() []rpc.API {
 		{
 			Namespace: "flip",
 			Version:   "1.0",
-			Service:   api.NewFlipApi(baseApi, node.fp, node.ipfsProxy, node.ceremony),
+			Service:   api.NewFlipApi(baseApi, node.fp, node.ipfsProxy, node.ceremony, node.archiver),
 			Public:    true,
 		},
 		{
@@ -377,12 +458,32 @@ func (node *Node) apis() []rpc.API {
 			Service:   api.NewBlockchainApi(baseApi, node.blockchain, node.ipfsProxy, node.txpool, node.downloader, node.pm),
 			Public:    true,
 		},
+		{
+			Namespace: "txpool",
+			Version:   "1.0",
+			Service:   api.NewTxPoolApi(node.txpool, node.ownTxWatcher),
+			Public:    true,
+		},
+		{
+			Namespace: "stats",
+			Version:   "1.0",
+			Service:   api.NewStatsApi(node.blockchain),
+			Public:    true,
+		},
+		{
+			Namespace: "invites",
+			Version:   "1.0",
+			Service:   api.NewInvitesApi(baseApi, node.invites),
+			Public:    true,
+		},
 	}
 }
 
 func (node *Node) generateSyntheticP2PKey() *ecdsa.PrivateKey {
-	hash := common.Hash(rlp.Hash([]byte("node-p2p-key")))
-	sig := node.secStore.Sign(hash.Bytes())
+	sig, err := node.secStore.SignSeed([]byte("node-p2p-key"))
+	if err != nil {
+		node.log.Crit("Cannot generate p2p key", "err", err)
+	}
 	p2pKey, _ := crypto.GenerateKeyFromSeed(bytes.NewReader(sig))
 	return p2pKey
 }
@@ -289,14 +289,18 @@ func (d *Downloader) getBestManifest() *snapshot.Manifest {
 
 	var best *snapshot.Manifest
 	for _, m := range manifests {
-		if (best == nil || best.Height < m.Height) && !d.sm.IsInvalidManifest(m.Cid) {
+		if d.sm.IsInvalidManifest(m.Key()) {
+			continue
+		}
+		// v1 manifests are still used, v2 is preferred for the same height since its chunks are verified on download
+		if best == nil || best.Height < m.Height || best.Height == m.Height && !best.IsV2() && m.IsV2() {
 			best = m
 		}
 	}
 	if best == nil {
 		d.log.Info("Snapshot manifest is not found")
 	} else {
-		d.log.Info("Found manifest", "height", best.Height)
+		d.log.Info("Found manifest", "height", best.Height, "v2", best.IsV2())
 	}
 	return best
 }
@@ -263,19 +263,11 @@ func (fs *fastSync) requestBatch(from, to uint64, ignoredPeer string) *batch {
 	return nil
 }
 
-func (fs *fastSync) postConsuming() error {
-	if fs.chain.PreliminaryHead.Height() != fs.manifest.Height {
-		return errors.New("preliminary head is lower than manifest's head")
-	}
-
-	if fs.chain.PreliminaryHead.Root() != fs.manifest.Root {
-		fs.sm.AddInvalidManifest(fs.manifest.Cid)
-		return errors.New("preliminary head's root doesn't equal manifest's root")
-	}
+func (fs *fastSync) recoverSnapshot() error {
 	fs.log.Info("Start loading of snapshot", "height", fs.manifest.Height)
 	filePath, err := fs.sm.DownloadSnapshot(fs.manifest)
 	if err != nil {
-		fs.sm.AddTimeoutManifest(fs.manifest.Cid)
+		fs.sm.AddTimeoutManifest(fs.manifest)
 		return errors.WithMessage(err, "snapshot's downloading has been failed")
 	}
 	fs.log.Info("Snapshot has been loaded", "height", fs.manifest.Height)
@@ -287,10 +279,53 @@ func (fs *fastSync) postConsuming() error {
 	err = fs.appState.State.RecoverSnapshot(fs.manifest, file)
 	file.Close()
 	if err != nil {
-		fs.sm.AddInvalidManifest(fs.manifest.Cid)
+		fs.sm.AddInvalidManifest(fs.manifest)
 		//TODO : add snapshot to ban list
 		return err
 	}
+	return nil
+}
+
+func (fs *fastSync) recoverSnapshotV2() error {
+	fs.log.Info("Start loading of snapshot v2", "height", fs.manifest.Height, "chunks", len(fs.manifest.Chunks))
+	if err := fs.sm.DownloadSnapshotV2(fs.manifest); err != nil {
+		if errors.Cause(err) == snapshot.ErrInvalidChunk {
+			fs.sm.AddInvalidManifest(fs.manifest)
+		} else {
+			fs.sm.AddTimeoutManifest(fs.manifest)
+		}
+		return errors.WithMessage(err, "snapshot's downloading has been failed")
+	}
+	fs.log.Info("Snapshot has been loaded", "height", fs.manifest.Height)
+
+	err := fs.appState.State.RecoverSnapshotV2(fs.manifest, func(idx int) (*snapshot.Block, error) {
+		return fs.sm.LoadChunk(fs.manifest, idx)
+	})
+	if err != nil {
+		fs.sm.AddInvalidManifest(fs.manifest)
+		return err
+	}
+	return nil
+}
+
+func (fs *fastSync) postConsuming() error {
+	if fs.chain.PreliminaryHead.Height() != fs.manifest.Height {
+		return errors.New("preliminary head is lower than manifest's head")
+	}
+
+	if fs.chain.PreliminaryHead.Root() != fs.manifest.Root {
+		fs.sm.AddInvalidManifest(fs.manifest)
+		return errors.New("preliminary head's root doesn't equal manifest's root")
+	}
+	var err error
+	if fs.manifest.IsV2() {
+		err = fs.recoverSnapshotV2()
+	} else {
+		err = fs.recoverSnapshot()
+	}
+	if err != nil {
+		return err
+	}
 
 	fs.stateDb.SaveForcedVersion(fs.chain.PreliminaryHead.Height())
 
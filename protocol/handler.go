@@ -22,20 +22,21 @@ import (
 )
 
 const (
-	Handshake         = 0x01
-	ProposeBlock      = 0x02
-	ProposeProof      = 0x03
-	Vote              = 0x04
-	NewTx             = 0x05
-	GetBlockByHash    = 0x06
-	GetBlocksRange    = 0x07
-	BlocksRange       = 0x08
-	FlipBody          = 0x09
-	FlipKey           = 0x0A
-	SnapshotManifest  = 0x0B
-	PushFlipCid       = 0x0C
-	PullFlip          = 0x0D
-	GetForkBlockRange = 0x0E
+	Handshake          = 0x01
+	ProposeBlock       = 0x02
+	ProposeProof       = 0x03
+	Vote               = 0x04
+	NewTx              = 0x05
+	GetBlockByHash     = 0x06
+	GetBlocksRange     = 0x07
+	BlocksRange        = 0x08
+	FlipBody           = 0x09
+	FlipKey            = 0x0A
+	SnapshotManifest   = 0x0B
+	PushFlipCid        = 0x0C
+	PullFlip           = 0x0D
+	GetForkBlockRange  = 0x0E
+	SnapshotManifestV2 = 0x0F
 )
 const (
 	DecodeErr              = 1
@@ -289,6 +290,16 @@ func (pm *ProtocolManager) handle(p *peer) error {
 			return errResp(DecodeErr, "%v: %v", msg, err)
 		}
 		p.manifest = manifest
+	case SnapshotManifestV2:
+		manifest := new(snapshot.ManifestV2)
+		if err := msg.Decode(manifest); err != nil {
+			return errResp(DecodeErr, "%v: %v", msg, err)
+		}
+		if err := manifest.Validate(); err != nil {
+			p.Log().Debug("Invalid snapshot manifest v2", "err", err)
+			break
+		}
+		p.manifestV2 = manifest.Manifest()
 	case PushFlipCid:
 		cid := new(flipCid)
 		if err := msg.Decode(cid); err != nil {
@@ -421,6 +432,9 @@ func (pm *ProtocolManager) GetKnownManifests() map[string]*snapshot.Manifest {
 		if peer.manifest != nil {
 			result[peer.id] = peer.manifest
 		}
+		if peer.manifestV2 != nil && (peer.manifest == nil || peer.manifestV2.Height >= peer.manifest.Height) {
+			result[peer.id] = peer.manifestV2
+		}
 	}
 	return result
 }
@@ -541,11 +555,12 @@ func (pm *ProtocolManager) syncTxPool(p *peer) {
 }
 
 func (pm *ProtocolManager) sendManifest(p *peer) {
-	manifest := pm.bcn.ReadSnapshotManifest()
-	if manifest == nil {
-		return
+	if manifest := pm.bcn.ReadSnapshotManifest(); manifest != nil {
+		p.sendMsg(SnapshotManifest, manifest, true)
+	}
+	if manifest := pm.bcn.ReadSnapshotManifestV2(); manifest != nil {
+		p.sendMsg(SnapshotManifestV2, manifest, true)
 	}
-	p.sendMsg(SnapshotManifest, manifest, true)
 }
 
 func (pm *ProtocolManager) syncFlipKeyPool(p *peer) {
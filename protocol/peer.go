@@ -30,6 +30,7 @@ type peer struct {
 	knownHeight          uint64
 	potentialHeight      uint64
 	manifest             *snapshot.Manifest
+	manifestV2           *snapshot.Manifest
 	queuedRequests       chan *request
 	highPriorityRequests chan *request
 	term                 chan struct{}
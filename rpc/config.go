@@ -2,6 +2,8 @@ package rpc
 
 import "fmt"
 
+const DefaultWSPort = 9010
+
 type Config struct {
 	// HTTPCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
@@ -35,6 +37,22 @@ type Config struct {
 	// for ephemeral nodes).
 	HTTPPort int `toml:",omitempty"`
 
+	// WSHost is the host interface on which to start the websocket RPC server. If
+	// this field is empty, no websocket API endpoint will be started. Subscriptions
+	// are available over websocket only.
+	WSHost string `toml:",omitempty"`
+
+	// WSPort is the TCP port number on which to start the websocket RPC server.
+	WSPort int `toml:",omitempty"`
+
+	// WSOrigins is the list of domain to accept websocket requests from. Please be
+	// aware that the server can only act upon the HTTP request the client sends and
+	// cannot verify the validity of the request header.
+	WSOrigins []string `toml:",omitempty"`
+
+	// WSModules is a list of API modules to expose via the websocket RPC interface.
+	WSModules []string `toml:",omitempty"`
+
 	APIKey    string
 	UseApiKey bool
 }
@@ -46,14 +64,24 @@ func (c *Config) HTTPEndpoint() string {
 	return fmt.Sprintf("%s:%d", c.HTTPHost, c.HTTPPort)
 }
 
+func (c *Config) WSEndpoint() string {
+	if c.WSHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.WSHost, c.WSPort)
+}
+
 func GetDefaultRPCConfig(host string, port int) *Config {
 	// DefaultConfig contains reasonable default settings.
 	return &Config{
 		HTTPCors:         []string{"*"},
 		HTTPHost:         host,
 		HTTPPort:         port,
-		HTTPModules:      []string{"net", "dna", "account", "flip", "bcn"},
+		HTTPModules:      []string{"net", "dna", "account", "flip", "bcn", "txpool", "stats", "invites"},
 		HTTPVirtualHosts: []string{"localhost"},
 		HTTPTimeouts:     DefaultHTTPTimeouts,
+		WSPort:           DefaultWSPort,
+		WSOrigins:        []string{"http://localhost"},
+		WSModules:        []string{"dna"},
 	}
 }
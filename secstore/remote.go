@@ -0,0 +1,242 @@
+package secstore
+
+import (
+	"bytes"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/hexutil"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/crypto/vrf/p256"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+)
+
+const (
+	// signerServiceName is the JSON-RPC service of the external signer, methods are called as "Signer.<Method>"
+	signerServiceName = "Signer"
+
+	defaultSignerTimeout = time.Minute
+)
+
+type KeyArgs struct{}
+
+type KeyReply struct {
+	Address common.Address `json:"address"`
+	PubKey  hexutil.Bytes  `json:"pubKey"`
+}
+
+// DataArgs contains either the seed or the rlp encoded vote header, tx or flip key
+type DataArgs struct {
+	Data hexutil.Bytes `json:"data"`
+}
+
+type SignatureReply struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+type VrfReply struct {
+	Index hexutil.Bytes `json:"index"`
+	Proof hexutil.Bytes `json:"proof"`
+}
+
+// RemoteSigner delegates key operations to the external signer process listening on the unix socket.
+// Signatures returned by the signer are verified against the signer key.
+type RemoteSigner struct {
+	socket  string
+	timeout time.Duration
+	address common.Address
+	pubKey  []byte
+
+	mutex  sync.Mutex
+	client *rpc.Client
+}
+
+// NewRemoteSigner connects to the signer and requests its key
+func NewRemoteSigner(socket string, timeout time.Duration) (*RemoteSigner, error) {
+	if timeout <= 0 {
+		timeout = defaultSignerTimeout
+	}
+	s := &RemoteSigner{
+		socket:  socket,
+		timeout: timeout,
+	}
+	reply := new(KeyReply)
+	if err := s.call("Key", &KeyArgs{}, reply); err != nil {
+		return nil, errors.Wrap(err, "cannot get the signer key")
+	}
+	pubKey, err := crypto.UnmarshalPubkey(reply.PubKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "signer returned invalid public key")
+	}
+	if crypto.PubkeyToAddress(*pubKey) != reply.Address {
+		return nil, errors.New("signer address does not match its public key")
+	}
+	s.address = reply.Address
+	s.pubKey = reply.PubKey
+	return s, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) PubKey() []byte {
+	return s.pubKey
+}
+
+func (s *RemoteSigner) SignVote(header *types.VoteHeader) ([]byte, error) {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	reply := new(SignatureReply)
+	if err := s.call("SignVote", &DataArgs{Data: data}, reply); err != nil {
+		return nil, err
+	}
+	if err := s.verifySignature(header.SignatureHash().Bytes(), reply.Signature); err != nil {
+		return nil, err
+	}
+	return reply.Signature, nil
+}
+
+func (s *RemoteSigner) SignSeed(seed []byte) ([]byte, error) {
+	reply := new(SignatureReply)
+	if err := s.call("SignSeed", &DataArgs{Data: seed}, reply); err != nil {
+		return nil, err
+	}
+	hash := rlp.Hash(seed)
+	if err := s.verifySignature(hash[:], reply.Signature); err != nil {
+		return nil, err
+	}
+	return reply.Signature, nil
+}
+
+func (s *RemoteSigner) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	reply := new(SignatureReply)
+	if err := s.call("SignTx", &DataArgs{Data: data}, reply); err != nil {
+		return nil, err
+	}
+	signedTx := &types.Transaction{
+		AccountNonce: tx.AccountNonce,
+		Epoch:        tx.Epoch,
+		Amount:       tx.Amount,
+		MaxFee:       tx.MaxFee,
+		Tips:         tx.Tips,
+		Payload:      tx.Payload,
+		To:           tx.To,
+		Type:         tx.Type,
+		Signature:    reply.Signature,
+	}
+	if sender, err := types.Sender(signedTx); err != nil || sender != s.address {
+		return nil, errors.New("signer returned invalid tx signature")
+	}
+	return signedTx, nil
+}
+
+func (s *RemoteSigner) SignFlipKey(fk *types.FlipKey) (*types.FlipKey, error) {
+	data, err := rlp.EncodeToBytes(fk)
+	if err != nil {
+		return nil, err
+	}
+	reply := new(SignatureReply)
+	if err := s.call("SignFlipKey", &DataArgs{Data: data}, reply); err != nil {
+		return nil, err
+	}
+	signedKey := &types.FlipKey{
+		Key:       fk.Key,
+		Epoch:     fk.Epoch,
+		Signature: reply.Signature,
+	}
+	if sender, err := types.SenderFlipKey(signedKey); err != nil || sender != s.address {
+		return nil, errors.New("signer returned invalid flip key signature")
+	}
+	return signedKey, nil
+}
+
+func (s *RemoteSigner) VrfEvaluate(data []byte) (index [32]byte, proof []byte, err error) {
+	reply := new(VrfReply)
+	if err := s.call("VrfEvaluate", &DataArgs{Data: data}, reply); err != nil {
+		return index, nil, err
+	}
+	pubKey, _ := crypto.UnmarshalPubkey(s.pubKey)
+	verifier, err := p256.NewVRFVerifier(pubKey)
+	if err != nil {
+		return index, nil, err
+	}
+	index, err = verifier.ProofToHash(data, reply.Proof)
+	if err != nil || !bytes.Equal(index[:], reply.Index) {
+		return index, nil, errors.New("signer returned invalid vrf proof")
+	}
+	return index, reply.Proof, nil
+}
+
+func (s *RemoteSigner) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+}
+
+func (s *RemoteSigner) verifySignature(data []byte, signature []byte) error {
+	pubKey, err := crypto.Ecrecover(data, signature)
+	if err != nil || !bytes.Equal(pubKey, s.pubKey) {
+		return errors.New("signer returned invalid signature")
+	}
+	return nil
+}
+
+// call sends the request to the signer, the connection is reestablished on the next call if it is broken
+func (s *RemoteSigner) call(method string, args interface{}, reply interface{}) error {
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
+	call := client.Go(signerServiceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			if _, ok := call.Error.(rpc.ServerError); !ok {
+				s.resetClient(client)
+			}
+			return errors.Wrapf(call.Error, "signer %v failed", method)
+		}
+		return nil
+	case <-time.After(s.timeout):
+		s.resetClient(client)
+		return errors.Errorf("signer %v timed out", method)
+	}
+}
+
+func (s *RemoteSigner) getClient() (*rpc.Client, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+	conn, err := net.DialTimeout("unix", s.socket, s.timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot connect to the signer")
+	}
+	s.client = jsonrpc.NewClient(conn)
+	return s.client, nil
+}
+
+func (s *RemoteSigner) resetClient(client *rpc.Client) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.client == client {
+		s.client.Close()
+		s.client = nil
+	}
+}
@@ -6,13 +6,15 @@ import (
 	"github.com/awnumar/memguard"
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
-	"github.com/idena-network/idena-go/crypto"
-	"github.com/idena-network/idena-go/crypto/vrf/p256"
+	"github.com/pkg/errors"
 	"os"
+	"sync"
 )
 
+// SecStore provides the node key operations, the key is kept either in memory or by the external signer
 type SecStore struct {
-	buffer *memguard.LockedBuffer
+	mutex  sync.RWMutex
+	signer Signer
 }
 
 func NewSecStore() *SecStore {
@@ -25,54 +27,68 @@ func NewSecStore() *SecStore {
 }
 
 func (s *SecStore) AddKey(secret []byte) {
-	buffer := memguard.NewBufferFromBytes(secret)
-	s.buffer = buffer
+	s.SetSigner(NewMemorySigner(secret))
+}
+
+// SetSigner replaces the current signer, the previous in-memory key is destroyed
+func (s *SecStore) SetSigner(signer Signer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if prev, ok := s.signer.(*memorySigner); ok {
+		prev.destroy()
+	}
+	s.signer = signer
+}
+
+func (s *SecStore) getSigner() Signer {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.signer
 }
 
 func (s *SecStore) SignTx(tx *types.Transaction) (*types.Transaction, error) {
-	sec, _ := crypto.ToECDSA(s.buffer.Bytes())
-	return types.SignTx(tx, sec)
+	return s.getSigner().SignTx(tx)
 }
 
 func (s *SecStore) SignFlipKey(fk *types.FlipKey) (*types.FlipKey, error) {
-	sec, _ := crypto.ToECDSA(s.buffer.Bytes())
-	return types.SignFlipKey(fk, sec)
+	return s.getSigner().SignFlipKey(fk)
 }
 
 func (s *SecStore) GetAddress() common.Address {
-	sec, _ := crypto.ToECDSA(s.buffer.Bytes())
-	return crypto.PubkeyToAddress(sec.PublicKey)
+	return s.getSigner().Address()
 }
 
 func (s *SecStore) GetPubKey() []byte {
-	sec, _ := crypto.ToECDSA(s.buffer.Bytes())
-	return crypto.FromECDSAPub(&sec.PublicKey)
+	return s.getSigner().PubKey()
 }
 
-func (s *SecStore) VrfEvaluate(data []byte) (index [32]byte, proof []byte) {
-	sec, _ := crypto.ToECDSA(s.buffer.Bytes())
-	signer, err := p256.NewVRFSigner(sec)
-	if err != nil {
-		panic(err)
-	}
-	return signer.Evaluate(data)
+func (s *SecStore) VrfEvaluate(data []byte) (index [32]byte, proof []byte, err error) {
+	return s.getSigner().VrfEvaluate(data)
 }
 
-func (s *SecStore) Sign(data []byte) []byte {
-	sec, _ := crypto.ToECDSA(s.buffer.Bytes())
-	sig, _ := crypto.Sign(data, sec)
-	return sig
+func (s *SecStore) SignVote(header *types.VoteHeader) ([]byte, error) {
+	return s.getSigner().SignVote(header)
+}
+
+func (s *SecStore) SignSeed(seed []byte) ([]byte, error) {
+	return s.getSigner().SignSeed(seed)
 }
 
 func (s *SecStore) Destroy() {
-	if s.buffer != nil {
-		s.buffer.Destroy()
+	switch signer := s.getSigner().(type) {
+	case *memorySigner:
+		signer.destroy()
+	case *RemoteSigner:
+		signer.Close()
 	}
 }
 
 func (s *SecStore) ExportKey(password string) (string, error) {
-	key := s.buffer.Bytes()
-	encrypted, err := crypto.Encrypt(key, password)
+	signer, ok := s.getSigner().(*memorySigner)
+	if !ok {
+		return "", errors.New("key is kept by the external signer and cannot be exported")
+	}
+	encrypted, err := signer.exportKey(password)
 	if err != nil {
 		return "", err
 	}
@@ -1,9 +1,18 @@
 package secstore
 
 import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
 	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/rlp"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestSecStore_VrfEvaluate(t *testing.T) {
@@ -11,8 +20,68 @@ func TestSecStore_VrfEvaluate(t *testing.T) {
 	key, _ := crypto.GenerateKey()
 	secStore.AddKey(crypto.FromECDSA(key))
 
-	index, proof := secStore.VrfEvaluate([]byte{0x1, 0x2})
-	index2, proof2 := secStore.VrfEvaluate([]byte{0x1, 0x2})
+	index, proof, err := secStore.VrfEvaluate([]byte{0x1, 0x2})
+	require.NoError(t, err)
+	index2, proof2, err := secStore.VrfEvaluate([]byte{0x1, 0x2})
+	require.NoError(t, err)
 	require.Equal(t, index, index2)
 	require.NotEqual(t, proof, proof2)
 }
+
+func TestRemoteSigner(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	key, _ := crypto.GenerateKey()
+	policy := NewConfirmSpendsPolicy(big.NewInt(10), func(tx *types.Transaction) bool {
+		return false
+	})
+	server, err := NewSignerServer(NewMemorySigner(crypto.FromECDSA(key)), policy)
+	require.NoError(err)
+	listener, err := net.Listen("unix", filepath.Join(dir, "signer.ipc"))
+	require.NoError(err)
+	defer listener.Close()
+	go server.Serve(listener)
+
+	signer, err := NewRemoteSigner(filepath.Join(dir, "signer.ipc"), time.Second)
+	require.NoError(err)
+	defer signer.Close()
+
+	secStore := NewSecStore()
+	secStore.SetSigner(signer)
+	require.Equal(crypto.PubkeyToAddress(key.PublicKey), secStore.GetAddress())
+
+	to := common.Address{0x1}
+	tx, err := secStore.SignTx(&types.Transaction{Type: types.SendTx, To: &to, Amount: big.NewInt(5)})
+	require.NoError(err)
+	sender, _ := types.Sender(tx)
+	require.Equal(secStore.GetAddress(), sender)
+
+	_, err = secStore.SignTx(&types.Transaction{Type: types.SendTx, To: &to, Amount: big.NewInt(11)})
+	require.Error(err)
+
+	header := &types.VoteHeader{Round: 1, VotedHash: common.Hash{0x2}}
+	sig, err := secStore.SignVote(header)
+	require.NoError(err)
+	pubKey, err := crypto.Ecrecover(header.SignatureHash().Bytes(), sig)
+	require.NoError(err)
+	require.Equal(secStore.GetPubKey(), pubKey)
+
+	seedSig, err := secStore.SignSeed([]byte("seed"))
+	require.NoError(err)
+	seedHash := rlp.Hash([]byte("seed"))
+	pubKey, err = crypto.Ecrecover(seedHash[:], seedSig)
+	require.NoError(err)
+	require.Equal(secStore.GetPubKey(), pubKey)
+
+	index, proof, err := secStore.VrfEvaluate([]byte{0x1, 0x2})
+	require.NoError(err)
+	require.NotNil(proof)
+	require.NotEqual([32]byte{}, index)
+
+	_, err = secStore.ExportKey("password")
+	require.Error(err)
+}
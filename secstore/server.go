@@ -0,0 +1,165 @@
+package secstore
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/log"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+	"math/big"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+)
+
+// Policy decides whether the signer server is allowed to sign the tx
+type Policy interface {
+	ApproveTx(tx *types.Transaction) error
+}
+
+type allowAllPolicy struct{}
+
+func NewAllowAllPolicy() Policy {
+	return &allowAllPolicy{}
+}
+
+func (p *allowAllPolicy) ApproveTx(tx *types.Transaction) error {
+	return nil
+}
+
+type confirmSpendsPolicy struct {
+	limit   *big.Int
+	confirm func(tx *types.Transaction) bool
+}
+
+// NewConfirmSpendsPolicy requires the confirmation of txs which transfer more coins than the limit,
+// other txs are signed without confirmation
+func NewConfirmSpendsPolicy(limit *big.Int, confirm func(tx *types.Transaction) bool) Policy {
+	if limit == nil {
+		limit = big.NewInt(0)
+	}
+	return &confirmSpendsPolicy{
+		limit:   limit,
+		confirm: confirm,
+	}
+}
+
+func (p *confirmSpendsPolicy) ApproveTx(tx *types.Transaction) error {
+	if !IsSpend(tx, p.limit) {
+		return nil
+	}
+	if !p.confirm(tx) {
+		return errors.New("tx is rejected by the signer")
+	}
+	return nil
+}
+
+// IsSpend returns true if the tx transfers more coins than the limit, KillTx always transfers the stake
+func IsSpend(tx *types.Transaction, limit *big.Int) bool {
+	return tx.Type == types.KillTx || tx.AmountOrZero().Cmp(limit) > 0
+}
+
+// SignerServer exposes the signer to the node over the JSON-RPC protocol, every connection is served separately.
+// Only typed payloads are signed, so the policy cannot be bypassed by signing the tx hash directly.
+type SignerServer struct {
+	server *rpc.Server
+	log    log.Logger
+}
+
+func NewSignerServer(signer Signer, policy Policy) (*SignerServer, error) {
+	logger := log.New("component", "signer")
+	server := rpc.NewServer()
+	if err := server.RegisterName(signerServiceName, &signerService{signer, policy, logger}); err != nil {
+		return nil, err
+	}
+	return &SignerServer{
+		server: server,
+		log:    logger,
+	}, nil
+}
+
+// Serve accepts connections until the listener is closed
+func (s *SignerServer) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		s.log.Debug("Signer client connected")
+		go s.server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+type signerService struct {
+	signer Signer
+	policy Policy
+	log    log.Logger
+}
+
+func (s *signerService) Key(args *KeyArgs, reply *KeyReply) error {
+	reply.Address = s.signer.Address()
+	reply.PubKey = s.signer.PubKey()
+	return nil
+}
+
+func (s *signerService) SignVote(args *DataArgs, reply *SignatureReply) error {
+	header := new(types.VoteHeader)
+	if err := rlp.DecodeBytes(args.Data, header); err != nil {
+		return errors.Wrap(err, "cannot decode vote header")
+	}
+	sig, err := s.signer.SignVote(header)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+func (s *signerService) SignSeed(args *DataArgs, reply *SignatureReply) error {
+	sig, err := s.signer.SignSeed(args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+func (s *signerService) SignTx(args *DataArgs, reply *SignatureReply) error {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(args.Data, tx); err != nil {
+		return errors.Wrap(err, "cannot decode tx")
+	}
+	if err := s.policy.ApproveTx(tx); err != nil {
+		s.log.Warn("Tx is not approved", "type", tx.Type, "nonce", tx.AccountNonce, "err", err)
+		return err
+	}
+	signedTx, err := s.signer.SignTx(tx)
+	if err != nil {
+		return err
+	}
+	s.log.Info("Tx signed", "hash", signedTx.Hash().Hex(), "type", tx.Type, "nonce", tx.AccountNonce)
+	reply.Signature = signedTx.Signature
+	return nil
+}
+
+func (s *signerService) SignFlipKey(args *DataArgs, reply *SignatureReply) error {
+	fk := new(types.FlipKey)
+	if err := rlp.DecodeBytes(args.Data, fk); err != nil {
+		return errors.Wrap(err, "cannot decode flip key")
+	}
+	signedKey, err := s.signer.SignFlipKey(fk)
+	if err != nil {
+		return err
+	}
+	reply.Signature = signedKey.Signature
+	return nil
+}
+
+func (s *signerService) VrfEvaluate(args *DataArgs, reply *VrfReply) error {
+	index, proof, err := s.signer.VrfEvaluate(args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Index = index[:]
+	reply.Proof = proof
+	return nil
+}
@@ -0,0 +1,117 @@
+package secstore
+
+import (
+	"crypto/ecdsa"
+	"github.com/awnumar/memguard"
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/crypto"
+	"github.com/idena-network/idena-go/crypto/vrf/p256"
+	"github.com/idena-network/idena-go/rlp"
+	"github.com/pkg/errors"
+)
+
+// Signer performs all operations which require the node key, the key itself is never exposed.
+// Arbitrary hashes are never signed: the signer hashes typed payloads itself, so a tx signature
+// can be obtained only through SignTx.
+type Signer interface {
+	Address() common.Address
+	PubKey() []byte
+	SignVote(header *types.VoteHeader) ([]byte, error)
+	// SignSeed signs the rlp hash of the seed, the signature is used to derive node keys and salts
+	SignSeed(seed []byte) ([]byte, error)
+	SignTx(tx *types.Transaction) (*types.Transaction, error)
+	SignFlipKey(fk *types.FlipKey) (*types.FlipKey, error)
+	VrfEvaluate(data []byte) (index [32]byte, proof []byte, err error)
+}
+
+// memorySigner keeps the key in the locked memory buffer of the node process
+type memorySigner struct {
+	buffer *memguard.LockedBuffer
+}
+
+func NewMemorySigner(secret []byte) Signer {
+	return &memorySigner{
+		buffer: memguard.NewBufferFromBytes(secret),
+	}
+}
+
+func (s *memorySigner) key() (*ecdsa.PrivateKey, error) {
+	if !s.buffer.IsAlive() {
+		return nil, errors.New("key is destroyed")
+	}
+	return crypto.ToECDSA(s.buffer.Bytes())
+}
+
+func (s *memorySigner) Address() common.Address {
+	sec, err := s.key()
+	if err != nil {
+		return common.Address{}
+	}
+	return crypto.PubkeyToAddress(sec.PublicKey)
+}
+
+func (s *memorySigner) PubKey() []byte {
+	sec, err := s.key()
+	if err != nil {
+		return nil
+	}
+	return crypto.FromECDSAPub(&sec.PublicKey)
+}
+
+func (s *memorySigner) SignVote(header *types.VoteHeader) ([]byte, error) {
+	sec, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(header.SignatureHash().Bytes(), sec)
+}
+
+func (s *memorySigner) SignSeed(seed []byte) ([]byte, error) {
+	sec, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	hash := rlp.Hash(seed)
+	return crypto.Sign(hash[:], sec)
+}
+
+func (s *memorySigner) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	sec, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, sec)
+}
+
+func (s *memorySigner) SignFlipKey(fk *types.FlipKey) (*types.FlipKey, error) {
+	sec, err := s.key()
+	if err != nil {
+		return nil, err
+	}
+	return types.SignFlipKey(fk, sec)
+}
+
+func (s *memorySigner) VrfEvaluate(data []byte) (index [32]byte, proof []byte, err error) {
+	sec, err := s.key()
+	if err != nil {
+		return index, nil, err
+	}
+	signer, err := p256.NewVRFSigner(sec)
+	if err != nil {
+		return index, nil, err
+	}
+	index, proof = signer.Evaluate(data)
+	return index, proof, nil
+}
+
+func (s *memorySigner) exportKey(password string) ([]byte, error) {
+	if !s.buffer.IsAlive() {
+		return nil, errors.New("key is destroyed")
+	}
+	return crypto.Encrypt(s.buffer.Bytes(), password)
+}
+
+func (s *memorySigner) destroy() {
+	s.buffer.Destroy()
+}
@@ -0,0 +1,257 @@
+package collector
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/events"
+	"github.com/idena-network/idena-go/log"
+	statsTypes "github.com/idena-network/idena-go/stats/types"
+	dbm "github.com/tendermint/tm-db"
+	"math/big"
+	"sync"
+)
+
+// persistentCollector keeps rewards and validation stats of blocks which finish epochs in the chain db.
+// Stats are collected while the block is being processed and saved once the block is added to the chain.
+type persistentCollector struct {
+	cfg      *config.StatsConfig
+	repo     *database.Repo
+	appState *appstate.AppState
+	log      log.Logger
+
+	mutex      sync.Mutex
+	enabled    bool
+	validation *statsTypes.ValidationStats
+	authors    *types.ValidationAuthors
+	block      *statsTypes.BlockStats
+	addresses  map[common.Address]*statsTypes.AddressStats
+}
+
+func NewPersistentBlockStatsCollector(cfg *config.StatsConfig, db dbm.DB, bus eventbus.Bus, appState *appstate.AppState) BlockStatsCollector {
+	c := &persistentCollector{
+		cfg:      cfg,
+		repo:     database.NewRepo(db),
+		appState: appState,
+		log:      log.New("component", "stats"),
+	}
+	_ = bus.Subscribe(events.AddBlockEventID, func(e eventbus.Event) {
+		newBlockEvent := e.(*events.NewBlockEvent)
+		c.persist(newBlockEvent.Block)
+	})
+	return c
+}
+
+func (c *persistentCollector) EnableCollecting() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reset()
+	c.enabled = true
+}
+
+func (c *persistentCollector) CompleteCollecting() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reset()
+	c.enabled = false
+}
+
+func (c *persistentCollector) reset() {
+	c.validation = nil
+	c.authors = nil
+	c.block = nil
+	c.addresses = nil
+}
+
+func (c *persistentCollector) SetValidation(validation *statsTypes.ValidationStats) {
+	c.update(func() {
+		c.validation = validation
+	})
+}
+
+func (c *persistentCollector) SetAuthors(authors *types.ValidationAuthors) {
+	c.update(func() {
+		c.authors = authors
+	})
+}
+
+func (c *persistentCollector) SetTotalReward(amount *big.Int) {
+	c.update(func() {
+		c.blockStats().TotalReward.Set(amount)
+	})
+}
+
+func (c *persistentCollector) SetTotalValidationReward(amount *big.Int) {
+	c.update(func() {
+		c.blockStats().ValidationReward.Set(amount)
+	})
+}
+
+func (c *persistentCollector) SetTotalFlipsReward(amount *big.Int) {
+	c.update(func() {
+		c.blockStats().FlipsReward.Set(amount)
+	})
+}
+
+func (c *persistentCollector) SetTotalInvitationsReward(amount *big.Int) {
+	c.update(func() {
+		c.blockStats().InvitationsReward.Set(amount)
+	})
+}
+
+func (c *persistentCollector) SetTotalFoundationPayouts(amount *big.Int) {
+	c.update(func() {
+		c.blockStats().FoundationPayouts.Set(amount)
+	})
+}
+
+func (c *persistentCollector) SetTotalZeroWalletFund(amount *big.Int) {
+	c.update(func() {
+		c.blockStats().ZeroWalletFund.Set(amount)
+	})
+}
+
+func (c *persistentCollector) AddValidationReward(addr common.Address, balance *big.Int, stake *big.Int) {
+	c.update(func() {
+		stats := c.addressStats(addr)
+		stats.ValidationReward.Add(stats.ValidationReward, balance)
+		stats.ValidationStake.Add(stats.ValidationStake, stake)
+	})
+}
+
+func (c *persistentCollector) AddFlipsReward(addr common.Address, balance *big.Int, stake *big.Int) {
+	c.update(func() {
+		stats := c.addressStats(addr)
+		stats.FlipsReward.Add(stats.FlipsReward, balance)
+		stats.FlipsStake.Add(stats.FlipsStake, stake)
+	})
+}
+
+func (c *persistentCollector) AddInvitationsReward(addr common.Address, balance *big.Int, stake *big.Int) {
+	c.update(func() {
+		stats := c.addressStats(addr)
+		stats.InvitationsReward.Add(stats.InvitationsReward, balance)
+		stats.InvitationsStake.Add(stats.InvitationsStake, stake)
+	})
+}
+
+func (c *persistentCollector) AddFoundationPayout(addr common.Address, balance *big.Int) {
+	c.update(func() {
+		stats := c.addressStats(addr)
+		stats.FoundationPayout.Add(stats.FoundationPayout, balance)
+	})
+}
+
+func (c *persistentCollector) AddZeroWalletFund(addr common.Address, balance *big.Int) {
+	c.update(func() {
+		stats := c.addressStats(addr)
+		stats.ZeroWalletFund.Add(stats.ZeroWalletFund, balance)
+	})
+}
+
+// update applies the change if the collecting is enabled, stats of validated proposals and of the state
+// simulations are ignored
+func (c *persistentCollector) update(change func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.enabled {
+		return
+	}
+	change()
+}
+
+func (c *persistentCollector) blockStats() *statsTypes.BlockStats {
+	if c.block == nil {
+		c.block = statsTypes.NewBlockStats()
+	}
+	return c.block
+}
+
+func (c *persistentCollector) addressStats(addr common.Address) *statsTypes.AddressStats {
+	if c.addresses == nil {
+		c.addresses = make(map[common.Address]*statsTypes.AddressStats)
+	}
+	stats, ok := c.addresses[addr]
+	if !ok {
+		stats = statsTypes.NewAddressStats(addr)
+		c.addresses[addr] = stats
+	}
+	return stats
+}
+
+func (c *persistentCollector) persist(block *types.Block) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.enabled || c.validation == nil && c.block == nil {
+		return
+	}
+	// the epoch has been already incremented by the block
+	epoch := c.appState.State.Epoch() - 1
+
+	blockStats := c.blockStats()
+	blockStats.Height = block.Height()
+	blockStats.Epoch = epoch
+
+	if c.validation != nil {
+		blockStats.ValidationFailed = c.validation.Failed
+		blockStats.Candidates = uint32(len(c.validation.IdentitiesPerAddr))
+		blockStats.Flips = uint32(len(c.validation.FlipCids))
+		for _, flip := range c.validation.FlipsPerIdx {
+			switch flip.Status {
+			// see ceremony.Qualified and ceremony.WeaklyQualified
+			case 1:
+				blockStats.QualifiedFlips++
+			case 2:
+				blockStats.WeaklyQualified++
+			}
+		}
+		for addr, identity := range c.validation.IdentitiesPerAddr {
+			stats := c.addressStats(addr)
+			stats.Candidate = true
+			stats.Approved = identity.Approved
+			stats.Missed = identity.Missed
+			stats.ShortPoints = uint32(identity.ShortPoint * 2)
+			stats.ShortFlips = identity.ShortFlips
+			stats.LongPoints = uint32(identity.LongPoint * 2)
+			stats.LongFlips = identity.LongFlips
+		}
+	}
+	if c.authors != nil {
+		blockStats.GoodAuthors = uint32(len(c.authors.GoodAuthors))
+		blockStats.BadAuthors = uint32(len(c.authors.BadAuthors))
+		for addr, author := range c.authors.GoodAuthors {
+			stats := c.addressStats(addr)
+			stats.StrongFlips = uint32(author.StrongFlips)
+			stats.WeakFlips = uint32(author.WeakFlips)
+			stats.SuccessfulInvites = uint32(author.SuccessfulInvites)
+		}
+		for addr := range c.authors.BadAuthors {
+			c.addressStats(addr).BadAuthor = true
+		}
+	}
+
+	addresses := make([]*statsTypes.AddressStats, 0, len(c.addresses))
+	for _, stats := range c.addresses {
+		stats.Height = blockStats.Height
+		stats.Epoch = epoch
+		addresses = append(addresses, stats)
+	}
+	c.repo.WriteBlockStats(blockStats, addresses)
+	c.log.Info("Epoch stats saved", "epoch", epoch, "height", blockStats.Height, "addresses", len(addresses))
+
+	c.deleteOutdated(epoch)
+}
+
+func (c *persistentCollector) deleteOutdated(epoch uint16) {
+	if c.cfg.RetentionEpochs == 0 || epoch < c.cfg.RetentionEpochs {
+		return
+	}
+	for _, stats := range c.repo.GetBlockStats() {
+		if stats.Epoch <= epoch-c.cfg.RetentionEpochs {
+			c.repo.DeleteBlockStats(stats.Height)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"github.com/idena-network/idena-go/blockchain/types"
+	"github.com/idena-network/idena-go/common"
+	"github.com/idena-network/idena-go/common/eventbus"
+	"github.com/idena-network/idena-go/config"
+	"github.com/idena-network/idena-go/core/appstate"
+	"github.com/idena-network/idena-go/database"
+	"github.com/idena-network/idena-go/events"
+	statsTypes "github.com/idena-network/idena-go/stats/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tm-db"
+	"math/big"
+	"testing"
+)
+
+func addBlock(bus eventbus.Bus, c BlockStatsCollector, appState *appstate.AppState, height uint64, collect func()) {
+	c.EnableCollecting()
+	defer c.CompleteCollecting()
+	collect()
+	appState.State.SetGlobalEpoch(appState.State.Epoch() + 1)
+	bus.Publish(&events.NewBlockEvent{
+		Block: &types.Block{Header: &types.Header{EmptyBlockHeader: &types.EmptyBlockHeader{Height: height}}},
+	})
+}
+
+func TestPersistentCollector(t *testing.T) {
+	require := require.New(t)
+	memdb := db.NewMemDB()
+	bus := eventbus.New()
+	appState := appstate.NewAppState(memdb, bus)
+	require.NoError(appState.Initialize(0))
+	appState.State.SetGlobalEpoch(1)
+
+	c := NewPersistentBlockStatsCollector(&config.StatsConfig{Enabled: true, RetentionEpochs: 2}, memdb, bus, appState)
+	repo := database.NewRepo(memdb)
+
+	addr := common.Address{0x1}
+	badAuthor := common.Address{0x2}
+
+	// stats of validated proposals are ignored
+	c.AddValidationReward(addr, big.NewInt(10), big.NewInt(10))
+
+	addBlock(bus, c, appState, 10, func() {
+		validation := statsTypes.NewValidationStats()
+		validation.IdentitiesPerAddr[addr] = &statsTypes.IdentityStats{ShortPoint: 4.5, ShortFlips: 5, Approved: true}
+		validation.FlipsPerIdx[0] = &statsTypes.FlipStats{Status: 1}
+		validation.FlipsPerIdx[1] = &statsTypes.FlipStats{Status: 2}
+		validation.FlipCids = [][]byte{{0x1}, {0x2}}
+		c.SetValidation(validation)
+		c.SetAuthors(&types.ValidationAuthors{
+			BadAuthors:  map[common.Address]struct{}{badAuthor: {}},
+			GoodAuthors: map[common.Address]*types.ValidationResult{addr: {StrongFlips: 1, WeakFlips: 1}},
+		})
+		c.SetTotalReward(big.NewInt(100))
+		c.AddValidationReward(addr, big.NewInt(8), big.NewInt(2))
+		c.AddFlipsReward(addr, big.NewInt(4), big.NewInt(1))
+	})
+
+	// blocks without stats are not stored
+	addBlock(bus, c, appState, 11, func() {})
+
+	stats := repo.GetBlockStats()
+	require.Len(stats, 1)
+	require.Equal(uint16(1), stats[0].Epoch)
+	require.Equal(uint64(10), stats[0].Height)
+	require.Equal(uint32(1), stats[0].QualifiedFlips)
+	require.Equal(uint32(1), stats[0].WeaklyQualified)
+	require.Equal(uint32(1), stats[0].BadAuthors)
+	require.Equal(int64(100), stats[0].TotalReward.Int64())
+
+	addrStats := repo.ReadAddressStats(10, addr)
+	require.NotNil(addrStats)
+	require.True(addrStats.Candidate)
+	require.Equal(uint32(9), addrStats.ShortPoints)
+	require.Equal(int64(8), addrStats.ValidationReward.Int64())
+	require.Equal(int64(1), addrStats.FlipsStake.Int64())
+	require.Equal(uint32(1), addrStats.StrongFlips)
+	require.True(repo.ReadAddressStats(10, badAuthor).BadAuthor)
+	require.Len(repo.GetAddressStats(10), 2)
+
+	for i := uint64(0); i < 2; i++ {
+		addBlock(bus, c, appState, 20+i, func() {
+			c.SetTotalReward(big.NewInt(100))
+			c.AddValidationReward(addr, big.NewInt(8), big.NewInt(2))
+		})
+	}
+
+	stats = repo.GetBlockStats()
+	require.Len(stats, 2)
+	require.Equal(uint64(20), stats[0].Height)
+	require.Nil(repo.ReadAddressStats(10, addr))
+	require.Empty(repo.GetAddressStats(10))
+}
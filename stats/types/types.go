@@ -3,6 +3,7 @@ package types
 import (
 	"github.com/idena-network/idena-go/blockchain/types"
 	"github.com/idena-network/idena-go/common"
+	"math/big"
 )
 
 type ValidationStats struct {
@@ -42,3 +43,77 @@ func NewValidationStats() *ValidationStats {
 		FlipsPerIdx:       make(map[int]*FlipStats),
 	}
 }
+
+// BlockStats contains totals of the block which has finished the epoch
+type BlockStats struct {
+	Height           uint64
+	Epoch            uint16
+	ValidationFailed bool
+	Candidates       uint32
+	Flips            uint32
+	QualifiedFlips   uint32
+	WeaklyQualified  uint32
+	GoodAuthors      uint32
+	BadAuthors       uint32
+
+	TotalReward       *big.Int
+	ValidationReward  *big.Int
+	FlipsReward       *big.Int
+	InvitationsReward *big.Int
+	FoundationPayouts *big.Int
+	ZeroWalletFund    *big.Int
+}
+
+// AddressStats contains rewards and validation results of the address in the block which has finished the epoch
+type AddressStats struct {
+	Address common.Address
+	Height  uint64
+	Epoch   uint16
+
+	ValidationReward  *big.Int
+	ValidationStake   *big.Int
+	FlipsReward       *big.Int
+	FlipsStake        *big.Int
+	InvitationsReward *big.Int
+	InvitationsStake  *big.Int
+	FoundationPayout  *big.Int
+	ZeroWalletFund    *big.Int
+
+	Candidate bool
+	Approved  bool
+	Missed    bool
+	// points are doubled to store halves as integers
+	ShortPoints       uint32
+	ShortFlips        uint32
+	LongPoints        uint32
+	LongFlips         uint32
+	StrongFlips       uint32
+	WeakFlips         uint32
+	SuccessfulInvites uint32
+	BadAuthor         bool
+}
+
+func NewBlockStats() *BlockStats {
+	return &BlockStats{
+		TotalReward:       new(big.Int),
+		ValidationReward:  new(big.Int),
+		FlipsReward:       new(big.Int),
+		InvitationsReward: new(big.Int),
+		FoundationPayouts: new(big.Int),
+		ZeroWalletFund:    new(big.Int),
+	}
+}
+
+func NewAddressStats(addr common.Address) *AddressStats {
+	return &AddressStats{
+		Address:           addr,
+		ValidationReward:  new(big.Int),
+		ValidationStake:   new(big.Int),
+		FlipsReward:       new(big.Int),
+		FlipsStake:        new(big.Int),
+		InvitationsReward: new(big.Int),
+		InvitationsStake:  new(big.Int),
+		FoundationPayout:  new(big.Int),
+		ZeroWalletFund:    new(big.Int),
+	}
+}
@@ -362,6 +362,208 @@ func TestTxPool_BuildBlockTransactionsWithPriorityTypes(t *testing.T) {
 	}
 }
 
+func TestTxPool_ReplaceByFee(t *testing.T) {
+	require := require.New(t)
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	alloc := make(map[common.Address]config.GenesisAllocation)
+	alloc[addr] = config.GenesisAllocation{
+		Balance: getAmount(100),
+	}
+
+	_, _, pool, _ := newBlockchain(true, alloc, -1, -1)
+
+	getTx := func(maxFee int64, tips int64) *types.Transaction {
+		tx := &types.Transaction{
+			AccountNonce: 1,
+			Type:         types.SendTx,
+			To:           &addr,
+			Amount:       getAmount(1),
+			MaxFee:       big.NewInt(maxFee),
+			Tips:         big.NewInt(tips),
+		}
+		tx, _ = types.SignTx(tx, key)
+		return tx
+	}
+
+	tx1 := getTx(100, 10)
+	require.NoError(pool.Add(tx1))
+
+	// fee is not bumped enough
+	require.Equal(mempool.ReplacementUnderpriced, pool.Add(getTx(105, 20)))
+	// tips are not bumped
+	require.Equal(mempool.ReplacementUnderpriced, pool.Add(getTx(200, 10)))
+
+	tx2 := getTx(110, 11)
+	require.NoError(pool.Add(tx2))
+
+	pending := pool.GetPendingByAddress(addr)
+	require.Len(pending, 1)
+	require.Equal(tx2.Hash(), pending[0].Hash())
+	require.Nil(pool.GetTx(tx1.Hash()))
+}
+
+func TestTxPool_EvictLowestFee(t *testing.T) {
+	require := require.New(t)
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	addr1 := crypto.PubkeyToAddress(key1.PublicKey)
+	addr2 := crypto.PubkeyToAddress(key2.PublicKey)
+
+	alloc := make(map[common.Address]config.GenesisAllocation)
+	alloc[addr1] = config.GenesisAllocation{
+		Balance: getAmount(100),
+	}
+	alloc[addr2] = config.GenesisAllocation{
+		Balance: getAmount(100),
+	}
+
+	_, _, pool, _ := newBlockchain(true, alloc, 2, 2)
+
+	getTx := func(nonce uint32, key *ecdsa.PrivateKey, maxFee int64) *types.Transaction {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		tx := &types.Transaction{
+			AccountNonce: nonce,
+			Type:         types.SendTx,
+			To:           &addr,
+			MaxFee:       big.NewInt(maxFee),
+		}
+		tx, _ = types.SignTx(tx, key)
+		return tx
+	}
+
+	tx1 := getTx(1, key1, 10)
+	tx2 := getTx(2, key1, 20)
+	require.NoError(pool.Add(tx1))
+	require.NoError(pool.Add(tx2))
+
+	// the same fee per byte is not enough to evict
+	require.Error(pool.Add(getTx(1, key2, 20)))
+
+	tx3 := getTx(1, key2, 30)
+	require.NoError(pool.Add(tx3))
+
+	// the last tx of the sender is evicted to avoid nonce gap
+	require.Nil(pool.GetTx(tx2.Hash()))
+	require.NotNil(pool.GetTx(tx1.Hash()))
+	require.NotNil(pool.GetTx(tx3.Hash()))
+}
+
+func TestTxPool_QueuedTxs(t *testing.T) {
+	require := require.New(t)
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	alloc := make(map[common.Address]config.GenesisAllocation)
+	alloc[addr] = config.GenesisAllocation{
+		Balance: getAmount(100),
+	}
+
+	_, _, pool, _ := newBlockchain(true, alloc, -1, -1)
+
+	require.NoError(pool.Add(GetTx(1, 0, key)))
+	require.NoError(pool.Add(GetTx(3, 0, key)))
+	require.NoError(pool.Add(GetTx(6, 0, key)))
+	require.NoError(pool.Add(GetTx(1, 1, key)))
+
+	senderTxs := pool.GetSenderTxs(addr)
+	require.Len(senderTxs.Executable, 1)
+	require.Len(senderTxs.Queued, 3)
+	require.Equal([]uint32{2, 4, 5}, senderTxs.MissingNonces)
+	require.Len(pool.BuildBlockTransactions(), 1)
+
+	require.NoError(pool.Add(GetTx(2, 0, key)))
+
+	senderTxs = pool.GetSenderTxs(addr)
+	require.Len(senderTxs.Executable, 3)
+	require.Len(senderTxs.Queued, 2)
+	require.Equal([]uint32{4, 5}, senderTxs.MissingNonces)
+	require.Len(pool.BuildBlockTransactions(), 3)
+}
+
+func TestTxPool_Inspect(t *testing.T) {
+	require := require.New(t)
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	alloc := make(map[common.Address]config.GenesisAllocation)
+	alloc[addr] = config.GenesisAllocation{
+		Balance: getAmount(100),
+	}
+
+	_, _, pool, _ := newBlockchain(true, alloc, -1, -1)
+
+	tx1 := GetTx(1, 0, key)
+	tx3 := GetTx(3, 0, key)
+	require.NoError(pool.Add(tx1))
+	require.NoError(pool.Add(tx3))
+
+	infos := pool.Inspect(&addr)
+	require.Len(infos, 2)
+	for _, info := range infos {
+		if info.Tx == tx1 {
+			require.True(info.Executable)
+			require.Equal(mempool.SkipReasonNotBuilt, info.SkipReason)
+		} else {
+			require.False(info.Executable)
+			require.Equal(mempool.SkipReasonQueued, info.SkipReason)
+		}
+	}
+
+	require.Len(pool.BuildBlockTransactions(), 1)
+	for _, info := range pool.Inspect(nil) {
+		if info.Tx == tx1 {
+			require.Empty(info.SkipReason)
+		}
+	}
+}
+
+func TestTxPool_DropStaleEpoch(t *testing.T) {
+	require := require.New(t)
+
+	_, app, pool, key := newBlockchain(true, nil, -1, -1)
+
+	tx := GetTx(1, 0, key)
+	require.NoError(pool.Add(tx))
+
+	app.State.IncEpoch()
+	app.Commit(nil)
+
+	pool.ResetTo(&types.Block{
+		Header: &types.Header{
+			ProposedHeader: &types.ProposedHeader{
+				Height: 2,
+			},
+		},
+		Body: &types.Body{},
+	})
+
+	require.Nil(pool.GetTx(tx.Hash()))
+	dropped := pool.GetDroppedOwnTxs()
+	require.Len(dropped, 1)
+	require.Equal(tx.Hash(), dropped[0].Tx.Hash())
+	require.Equal(mempool.DropReasonStaleEpoch, dropped[0].Reason)
+}
+
+func TestTxPool_ReserveNonce(t *testing.T) {
+	require := require.New(t)
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	_, _, pool, _ := newBlockchain(true, nil, -1, -1)
+
+	require.Equal(uint32(1), pool.ReserveNonce(addr, 0))
+	require.Equal(uint32(2), pool.ReserveNonce(addr, 0))
+	require.Equal(uint32(3), pool.ReserveNonces(addr, 0, 3))
+	require.Equal(uint32(6), pool.ReserveNonce(addr, 0))
+
+	// released nonce is reused to avoid nonce gap
+	pool.ReleaseNonce(addr, 0, 2)
+	require.Equal(uint32(2), pool.ReserveNonce(addr, 0))
+	require.Equal(uint32(7), pool.ReserveNonce(addr, 0))
+}
+
 func newBlockchain(withIdentity bool, alloc map[common.Address]config.GenesisAllocation, totalTxLimit int, addrTxLimit int) (*blockchain.Blockchain, *appstate.AppState, *mempool.TxPool, *ecdsa.PrivateKey) {
 	conf := blockchain.GetDefaultConsensusConfig(false)
 	conf.MinFeePerByte = big.NewInt(0)